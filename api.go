@@ -2,26 +2,84 @@ package cagc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // GetVersion gets the version of the Coolify API
-func (c *Client) GetVersion(ctx context.Context) (float32, error) {
+func (c *Client) GetVersion(ctx context.Context, opts ...CallOption) (float32, error) {
 	var version float32
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/version", nil, &version)
+	err := c.doRequest(ctx, "version", http.MethodGet, apiPath("version"), nil, &version, opts...)
 	return version, err
 }
 
 // EnableAPI enables the Coolify API (requires root permissions)
-func (c *Client) EnableAPI(ctx context.Context) (*MessageResponse, error) {
+func (c *Client) EnableAPI(ctx context.Context, opts ...CallOption) (*MessageResponse, error) {
 	var response MessageResponse
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/enable", nil, &response)
+	err := c.doRequest(ctx, "enable-api", http.MethodGet, apiPath("enable"), nil, &response, opts...)
 	return &response, err
 }
 
 // DisableAPI disables the Coolify API (requires root permissions)
-func (c *Client) DisableAPI(ctx context.Context) (*MessageResponse, error) {
+func (c *Client) DisableAPI(ctx context.Context, opts ...CallOption) (*MessageResponse, error) {
 	var response MessageResponse
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/disable", nil, &response)
+	err := c.doRequest(ctx, "disable-api", http.MethodGet, apiPath("disable"), nil, &response, opts...)
 	return &response, err
 }
+
+// ErrUnhealthy is returned by Health when the instance answers with anything
+// other than "OK"
+var ErrUnhealthy = errors.New("cagc: instance is not healthy")
+
+// Health checks the healthcheck endpoint of the instance, which answers with
+// a plain "OK" when it is up
+func (c *Client) Health(ctx context.Context, opts ...CallOption) error {
+	call, err := c.do(ctx, Request{Operation: "healthcheck", Method: http.MethodGet, Path: apiPath("health")}, nil, opts...)
+	if err != nil {
+		return err
+	}
+	body := strings.Trim(strings.TrimSpace(string(call.responseBody)), `"`)
+	if !strings.EqualFold(body, "OK") {
+		return fmt.Errorf("%w: health endpoint answered %q", ErrUnhealthy, body)
+	}
+	return nil
+}
+
+// WaitUntilHealthy polls the instance every interval, 2 seconds if zero,
+// until the health endpoint reports it up and the version endpoint accepts
+// the client's token, e.g. after an upgrade. It returns the last failure
+// together with the context error when ctx ends first.
+func (c *Client) WaitUntilHealthy(ctx context.Context, interval time.Duration, opts ...CallOption) error {
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		err := c.Health(ctx, opts...)
+		if err == nil {
+			// The version endpoint requires a valid token; its body is not needed
+			_, err = c.do(ctx, Request{Operation: "version", Method: http.MethodGet, Path: apiPath("version")}, nil, opts...)
+			if err == nil {
+				return nil
+			}
+		}
+		if ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr == nil {
+				return fmt.Errorf("cagc: waiting for instance to become healthy: %w", ctx.Err())
+			}
+			return fmt.Errorf("cagc: waiting for instance to become healthy: %w; last error: %w", ctx.Err(), lastErr)
+		case <-ticker.C:
+		}
+	}
+}
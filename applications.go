@@ -8,73 +8,73 @@ import (
 )
 
 // ListApplications lists all applications
-func (c *Client) ListApplications(ctx context.Context) ([]Application, error) {
+func (c *Client) ListApplications(ctx context.Context, opts ...CallOption) ([]Application, error) {
 	var applications []Application
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/applications", nil, &applications)
+	err := c.doRequest(ctx, "list-applications", http.MethodGet, apiPath("applications"), nil, &applications, opts...)
 	return applications, err
 }
 
 // GetApplication gets an application by UUID
-func (c *Client) GetApplication(ctx context.Context, uuid string) (*Application, error) {
-	path := fmt.Sprintf("/applications/%s", uuid)
+func (c *Client) GetApplication(ctx context.Context, uuid string, opts ...CallOption) (*Application, error) {
+	path := apiPath("applications", uuid)
 	var application Application
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &application)
+	err := c.doRequest(ctx, "get-application-by-uuid", http.MethodGet, path, nil, &application, opts...)
 	return &application, err
 }
 
 // CreatePublicApplication creates a new application based on a public git repository
-func (c *Client) CreatePublicApplication(ctx context.Context, app Application) (*CreateResponse, error) {
+func (c *Client) CreatePublicApplication(ctx context.Context, app Application, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/applications/public", app, &response)
+	err := c.doRequest(ctx, "create-public-application", http.MethodPost, apiPath("applications", "public"), app, &response, opts...)
 	return &response, err
 }
 
 // CreatePrivateGithubAppApplication creates a new application based on a private repo through Github App
-func (c *Client) CreatePrivateGithubAppApplication(ctx context.Context, app Application) (*CreateResponse, error) {
+func (c *Client) CreatePrivateGithubAppApplication(ctx context.Context, app Application, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/applications/private-github-app", app, &response)
+	err := c.doRequest(ctx, "create-private-github-app-application", http.MethodPost, apiPath("applications", "private-github-app"), app, &response, opts...)
 	return &response, err
 }
 
 // CreatePrivateDeployKeyApplication creates a new application based on a private repo through Deploy Key
-func (c *Client) CreatePrivateDeployKeyApplication(ctx context.Context, app Application) (*CreateResponse, error) {
+func (c *Client) CreatePrivateDeployKeyApplication(ctx context.Context, app Application, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/applications/private-deploy-key", app, &response)
+	err := c.doRequest(ctx, "create-private-deploy-key-application", http.MethodPost, apiPath("applications", "private-deploy-key"), app, &response, opts...)
 	return &response, err
 }
 
 // CreateDockerfileApplication creates a new application based on a Dockerfile
-func (c *Client) CreateDockerfileApplication(ctx context.Context, app Application) (*CreateResponse, error) {
+func (c *Client) CreateDockerfileApplication(ctx context.Context, app Application, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/applications/dockerfile", app, &response)
+	err := c.doRequest(ctx, "create-dockerfile-application", http.MethodPost, apiPath("applications", "dockerfile"), app, &response, opts...)
 	return &response, err
 }
 
 // CreateDockerImageApplication creates a new application based on a Docker image
-func (c *Client) CreateDockerImageApplication(ctx context.Context, app Application) (*CreateResponse, error) {
+func (c *Client) CreateDockerImageApplication(ctx context.Context, app Application, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/applications/dockerimage", app, &response)
+	err := c.doRequest(ctx, "create-dockerimage-application", http.MethodPost, apiPath("applications", "dockerimage"), app, &response, opts...)
 	return &response, err
 }
 
 // CreateDockerComposeApplication creates a new application based on a docker-compose file
-func (c *Client) CreateDockerComposeApplication(ctx context.Context, app Application) (*CreateResponse, error) {
+func (c *Client) CreateDockerComposeApplication(ctx context.Context, app Application, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/applications/dockercompose", app, &response)
+	err := c.doRequest(ctx, "create-dockercompose-application", http.MethodPost, apiPath("applications", "dockercompose"), app, &response, opts...)
 	return &response, err
 }
 
 // UpdateApplication updates an existing application
-func (c *Client) UpdateApplication(ctx context.Context, uuid string, app Application) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s", uuid)
+func (c *Client) UpdateApplication(ctx context.Context, uuid string, app Application, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, app, &response)
+	err := c.doRequest(ctx, "update-application-by-uuid", http.MethodPatch, path, app, &response, opts...)
 	return &response, err
 }
 
 // DeleteApplication deletes an application
-func (c *Client) DeleteApplication(ctx context.Context, uuid string, deleteConfigurations, deleteVolumes, dockerCleanup, deleteConnectedNetworks bool) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s", uuid)
+func (c *Client) DeleteApplication(ctx context.Context, uuid string, deleteConfigurations, deleteVolumes, dockerCleanup, deleteConnectedNetworks bool, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", uuid)
 	query := url.Values{}
 	query.Add("delete_configurations", fmt.Sprintf("%t", deleteConfigurations))
 	query.Add("delete_volumes", fmt.Sprintf("%t", deleteVolumes))
@@ -82,92 +82,92 @@ func (c *Client) DeleteApplication(ctx context.Context, uuid string, deleteConfi
 	query.Add("delete_connected_networks", fmt.Sprintf("%t", deleteConnectedNetworks))
 
 	if len(query) > 0 {
-		path = fmt.Sprintf("%s?%s", path, query.Encode())
+		path += "?" + query.Encode()
 	}
 
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-application-by-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
 
 // StartApplication starts an application
-func (c *Client) StartApplication(ctx context.Context, uuid string, force, instantDeploy bool) (*DeploymentResponse, error) {
-	path := fmt.Sprintf("/applications/%s/start", uuid)
+func (c *Client) StartApplication(ctx context.Context, uuid string, force, instantDeploy bool, opts ...CallOption) (*DeploymentResponse, error) {
+	path := apiPath("applications", uuid, "start")
 	query := url.Values{}
 	query.Add("force", fmt.Sprintf("%t", force))
 	query.Add("instant_deploy", fmt.Sprintf("%t", instantDeploy))
 
 	if len(query) > 0 {
-		path = fmt.Sprintf("%s?%s", path, query.Encode())
+		path += "?" + query.Encode()
 	}
 
 	var response DeploymentResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "start-application-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // StopApplication stops an application
-func (c *Client) StopApplication(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s/stop", uuid)
+func (c *Client) StopApplication(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", uuid, "stop")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "stop-application-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // RestartApplication restarts an application
-func (c *Client) RestartApplication(ctx context.Context, uuid string) (*DeploymentResponse, error) {
-	path := fmt.Sprintf("/applications/%s/restart", uuid)
+func (c *Client) RestartApplication(ctx context.Context, uuid string, opts ...CallOption) (*DeploymentResponse, error) {
+	path := apiPath("applications", uuid, "restart")
 	var response DeploymentResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "restart-application-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // ExecuteCommand executes a command on an application's container
-func (c *Client) ExecuteCommand(ctx context.Context, uuid string, command string) (*CommandResponse, error) {
-	path := fmt.Sprintf("/applications/%s/execute", uuid)
+func (c *Client) ExecuteCommand(ctx context.Context, uuid string, command string, opts ...CallOption) (*CommandResponse, error) {
+	path := apiPath("applications", uuid, "execute")
 	req := map[string]string{"command": command}
 	var response CommandResponse
-	err := c.doRequest(ctx, http.MethodPost, path, req, &response)
+	err := c.doRequest(ctx, "execute-command-application", http.MethodPost, path, req, &response, opts...)
 	return &response, err
 }
 
 // ListApplicationEnvs lists all environment variables for an application
-func (c *Client) ListApplicationEnvs(ctx context.Context, uuid string) ([]EnvironmentVariable, error) {
-	path := fmt.Sprintf("/applications/%s/envs", uuid)
+func (c *Client) ListApplicationEnvs(ctx context.Context, uuid string, opts ...CallOption) ([]EnvironmentVariable, error) {
+	path := apiPath("applications", uuid, "envs")
 	var envs []EnvironmentVariable
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &envs)
+	err := c.doRequest(ctx, "list-envs-by-application-uuid", http.MethodGet, path, nil, &envs, opts...)
 	return envs, err
 }
 
 // CreateApplicationEnv creates a new environment variable for an application
-func (c *Client) CreateApplicationEnv(ctx context.Context, appUUID string, env EnvironmentVariable) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s/envs", appUUID)
+func (c *Client) CreateApplicationEnv(ctx context.Context, appUUID string, env EnvironmentVariable, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", appUUID, "envs")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, path, env, &response)
+	err := c.doRequest(ctx, "create-env-by-application-uuid", http.MethodPost, path, env, &response, opts...)
 	return &response, err
 }
 
 // UpdateApplicationEnv updates an environment variable for an application
-func (c *Client) UpdateApplicationEnv(ctx context.Context, appUUID string, env EnvironmentVariable) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s/envs", appUUID)
+func (c *Client) UpdateApplicationEnv(ctx context.Context, appUUID string, env EnvironmentVariable, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", appUUID, "envs")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, env, &response)
+	err := c.doRequest(ctx, "update-env-by-application-uuid", http.MethodPatch, path, env, &response, opts...)
 	return &response, err
 }
 
 // DeleteApplicationEnv deletes an environment variable for an application
-func (c *Client) DeleteApplicationEnv(ctx context.Context, appUUID string, envUUID string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s/envs/%s", appUUID, envUUID)
+func (c *Client) DeleteApplicationEnv(ctx context.Context, appUUID string, envUUID string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", appUUID, "envs", envUUID)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-env-by-application-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
 
 // UpdateApplicationEnvsBulk updates multiple environment variables for an application
-func (c *Client) UpdateApplicationEnvsBulk(ctx context.Context, appUUID string, envs []EnvironmentVariable) (*CreateResponse, error) {
-	path := fmt.Sprintf("/applications/%s/envs/bulk", appUUID)
+func (c *Client) UpdateApplicationEnvsBulk(ctx context.Context, appUUID string, envs []EnvironmentVariable, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("applications", appUUID, "envs", "bulk")
 	req := map[string][]EnvironmentVariable{"data": envs}
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, req, &response)
+	err := c.doRequest(ctx, "update-envs-by-application-uuid", http.MethodPatch, path, req, &response, opts...)
 	return &response, err
 }
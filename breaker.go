@@ -0,0 +1,173 @@
+package cagc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker of the client is open
+var ErrCircuitOpen = errors.New("cagc: circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails every request with ErrCircuitOpen until the cooldown ends
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through to decide whether
+	// to close or reopen the circuit
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures the circuit breaker enabled with
+// WithCircuitBreaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed attempts that
+	// opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before a probe is let
+	// through. Defaults to 30 seconds.
+	Cooldown time.Duration
+	// OnStateChange, if set, is called after every state change
+	OnStateChange func(from, to CircuitState)
+}
+
+// WithCircuitBreaker stops sending requests to an instance that keeps
+// failing. Every attempt, including retries, counts: network errors and 5xx
+// responses are failures, any other response is a success. After
+// FailureThreshold consecutive failures the circuit opens and requests fail
+// fast with ErrCircuitOpen; after the cooldown a single probe decides whether
+// it closes again.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Option {
+	return optionFunc(func(c *Client) error {
+		if cfg.FailureThreshold < 0 || cfg.Cooldown < 0 {
+			return errors.New("cagc: WithCircuitBreaker requires a non-negative threshold and cooldown")
+		}
+		if cfg.FailureThreshold == 0 {
+			cfg.FailureThreshold = 5
+		}
+		if cfg.Cooldown == 0 {
+			cfg.Cooldown = 30 * time.Second
+		}
+		c.breaker = &circuitBreaker{cfg: cfg}
+		return nil
+	})
+}
+
+// CircuitState returns the state of the circuit breaker, or CircuitClosed
+// when none is configured
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	if c.breaker.state == CircuitOpen && !time.Now().Before(c.breaker.openUntil) {
+		return CircuitHalfOpen
+	}
+	return c.breaker.state
+}
+
+// circuitBreaker tracks consecutive failures of a client
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow reports whether an attempt may be sent and whether it is the probe
+// of a half-open circuit. When it returns nil the caller must report the
+// outcome with done, passing probe back.
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	from := b.state
+	if b.state == CircuitOpen && !time.Now().Before(b.openUntil) {
+		b.state = CircuitHalfOpen
+	}
+	switch b.state {
+	case CircuitOpen:
+		err = ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probing {
+			err = ErrCircuitOpen
+		} else {
+			b.probing = true
+			probe = true
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.changed(from, to)
+	return probe, err
+}
+
+// done records the outcome of an attempt let through by allow. An attempt
+// that ended because its own context was canceled counts neither way. While
+// the circuit is not closed only the probe decides its state; attempts that
+// were admitted before it opened and finish late are ignored.
+func (b *circuitBreaker) done(probe bool, resp *http.Response, err error, canceled bool) {
+	b.mu.Lock()
+	from := b.state
+	if probe {
+		b.probing = false
+	}
+	failed := err != nil || (resp != nil && resp.StatusCode >= 500)
+
+	switch {
+	case canceled:
+	case probe:
+		if failed {
+			b.failures++
+			b.state = CircuitOpen
+			b.openUntil = time.Now().Add(b.cfg.Cooldown)
+		} else {
+			b.failures = 0
+			b.state = CircuitClosed
+		}
+	case b.state != CircuitClosed:
+	case failed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.state = CircuitOpen
+			b.openUntil = time.Now().Add(b.cfg.Cooldown)
+		}
+	default:
+		b.failures = 0
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.changed(from, to)
+}
+
+// changed calls the OnStateChange callback for a state transition
+func (b *circuitBreaker) changed(from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, to)
+	}
+}
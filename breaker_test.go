@@ -0,0 +1,214 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// outcome is the result of an attempt reported to a circuit breaker
+type outcome int
+
+const (
+	none outcome = iota - 1
+	succeeded
+	failed
+	canceled
+)
+
+// report passes an outcome to b.done
+func report(b *circuitBreaker, probe bool, o outcome) {
+	switch o {
+	case succeeded:
+		b.done(probe, &http.Response{StatusCode: http.StatusOK}, nil, false)
+	case failed:
+		b.done(probe, &http.Response{StatusCode: http.StatusBadGateway}, nil, false)
+	case canceled:
+		b.done(probe, nil, context.Canceled, true)
+	}
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	const cooldown = 20 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		outcomes []outcome
+		wait     bool
+		probe    outcome
+		want     CircuitState
+	}{
+		{"stays closed below the threshold", []outcome{failed, failed}, false, none, CircuitClosed},
+		{"success resets the count", []outcome{failed, failed, succeeded, failed, failed}, false, none, CircuitClosed},
+		{"opens at the threshold", []outcome{failed, failed, failed}, false, none, CircuitOpen},
+		{"cancellations do not count", []outcome{failed, canceled, failed, canceled}, false, none, CircuitClosed},
+		{"half-open after the cooldown", []outcome{failed, failed, failed}, true, none, CircuitHalfOpen},
+		{"successful probe closes", []outcome{failed, failed, failed}, true, succeeded, CircuitClosed},
+		{"failed probe reopens", []outcome{failed, failed, failed}, true, failed, CircuitOpen},
+		{"canceled probe leaves it half-open", []outcome{failed, failed, failed}, true, canceled, CircuitHalfOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &circuitBreaker{cfg: CircuitBreakerConfig{FailureThreshold: 3, Cooldown: cooldown}}
+			c := &Client{breaker: b}
+
+			for _, o := range tt.outcomes {
+				probe, err := b.allow()
+				if err != nil {
+					t.Fatalf("attempt rejected while closed: %v", err)
+				}
+				report(b, probe, o)
+			}
+			if tt.wait {
+				time.Sleep(cooldown + 5*time.Millisecond)
+			}
+			if tt.probe != none {
+				probe, err := b.allow()
+				if err != nil || !probe {
+					t.Fatalf("allow after the cooldown = %v, %v; want a probe", probe, err)
+				}
+				report(b, probe, tt.probe)
+			}
+
+			if got := c.CircuitState(); got != tt.want {
+				t.Errorf("state = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	b := &circuitBreaker{cfg: CircuitBreakerConfig{FailureThreshold: 1, Cooldown: 10 * time.Millisecond}}
+	probe, _ := b.allow()
+	report(b, probe, failed)
+
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow while open = %v, want ErrCircuitOpen", err)
+	}
+	time.Sleep(15 * time.Millisecond)
+
+	if probe, err := b.allow(); err != nil || !probe {
+		t.Fatalf("first allow after the cooldown = %v, %v; want a probe", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second allow while probing = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleOutcomes(t *testing.T) {
+	tests := []struct {
+		name  string
+		stale outcome
+		probe outcome
+		want  CircuitState
+	}{
+		{"stale success does not close", succeeded, none, CircuitHalfOpen},
+		{"stale failure does not reopen", failed, none, CircuitHalfOpen},
+		{"probe failure reopens after a stale success", succeeded, failed, CircuitOpen},
+		{"probe success closes after a stale failure", failed, succeeded, CircuitClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &circuitBreaker{cfg: CircuitBreakerConfig{FailureThreshold: 1, Cooldown: 10 * time.Millisecond}}
+			c := &Client{breaker: b}
+
+			// A is admitted while the circuit is closed
+			probeA, err := b.allow()
+			if err != nil || probeA {
+				t.Fatalf("allow A = %v, %v", probeA, err)
+			}
+
+			// B fails and opens the circuit
+			probeB, _ := b.allow()
+			report(b, probeB, failed)
+			time.Sleep(15 * time.Millisecond)
+
+			// C is the probe
+			probeC, err := b.allow()
+			if err != nil || !probeC {
+				t.Fatalf("allow C = %v, %v; want a probe", probeC, err)
+			}
+
+			// A finishes late, while C is still in flight
+			report(b, probeA, tt.stale)
+			if got := c.CircuitState(); got != CircuitHalfOpen {
+				t.Fatalf("state after the stale outcome = %v, want half-open", got)
+			}
+			if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Errorf("allow while C is in flight = %v, want ErrCircuitOpen", err)
+			}
+
+			if tt.probe != none {
+				report(b, probeC, tt.probe)
+			}
+			if got := c.CircuitState(); got != tt.want {
+				t.Errorf("state = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerClient(t *testing.T) {
+	var requests int32
+	var healthy atomic.Bool
+	var mu sync.Mutex
+	var changes []string
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}, WithCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Cooldown:         20 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			mu.Lock()
+			changes = append(changes, from.String()+"->"+to.String())
+			mu.Unlock()
+		},
+	}))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListApplications(ctx); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d failed fast before the threshold", i)
+		}
+	}
+	if _, err := c.ListApplications(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got %v, want ErrCircuitOpen", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+
+	healthy.Store(true)
+	time.Sleep(25 * time.Millisecond)
+	if _, err := c.ListApplications(ctx); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if state := c.CircuitState(); state != CircuitClosed {
+		t.Errorf("state = %v, want closed", state)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("state changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("state changes = %v, want %v", changes, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package cagc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheConfig configures the response cache enabled with WithCache. Entries
+// are grouped by resource kind, the first segment of the API path such as
+// "applications", "servers", "resources" or "security" (private keys).
+type CacheConfig struct {
+	// DefaultTTL applies to kinds without an entry in TTL. Zero disables
+	// caching for those kinds.
+	DefaultTTL time.Duration
+	// TTL overrides the time to live per resource kind
+	TTL map[string]time.Duration
+	// ETags keeps expired entries that carry an ETag and revalidates them
+	// with If-None-Match instead of downloading them again
+	ETags bool
+}
+
+// CacheStats reports the effectiveness of the response cache
+type CacheStats struct {
+	// Hits counts calls answered from a fresh entry
+	Hits int64
+	// Misses counts cacheable calls that had to go to the server
+	Misses int64
+	// Revalidations counts expired entries confirmed with a 304 response
+	Revalidations int64
+	// Invalidations counts entries dropped because of a mutation
+	Invalidations int64
+	// Entries is the number of entries currently held
+	Entries int
+}
+
+// WithCache caches the responses of List and Get calls in memory, keyed by
+// URL and the headers set for the call. A successful Create, Update, Delete,
+// Start, Stop, Restart, Execute or Deploy call clears the entries of its
+// resource kind, as well as those of the kinds that embed other resources:
+// "resources", "servers", "projects" and "deployments".
+func WithCache(cfg CacheConfig) Option {
+	return optionFunc(func(c *Client) error {
+		if cfg.DefaultTTL < 0 {
+			return errors.New("cagc: WithCache requires a non-negative TTL")
+		}
+		for _, ttl := range cfg.TTL {
+			if ttl < 0 {
+				return errors.New("cagc: WithCache requires a non-negative TTL")
+			}
+		}
+		c.cache = &responseCache{cfg: cfg, entries: make(map[string]*cacheEntry)}
+		return nil
+	})
+}
+
+// CacheStats returns the statistics of the response cache, or zero values
+// when caching is disabled
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.snapshot()
+}
+
+// InvalidateCache drops the cached entries of the given resource kinds, or
+// every entry when no kind is given
+func (c *Client) InvalidateCache(kinds ...string) {
+	if c.cache == nil {
+		return
+	}
+	if len(kinds) == 0 {
+		c.cache.invalidateAll()
+		return
+	}
+	c.cache.invalidate(kinds...)
+}
+
+// mutatingPrefixes are the operationId prefixes of calls that change state
+var mutatingPrefixes = []string{
+	"create-", "update-", "delete-", "start-", "stop-", "restart-", "execute-", "deploy-", "enable-", "disable-",
+}
+
+// dependentKinds are cleared by every mutation in addition to its own kind.
+// Their responses embed other kinds: the aggregated resource listing, the
+// resources and domains of a server, the applications, databases and services
+// of a project environment, and the running deployments.
+var dependentKinds = []string{"resources", "servers", "projects", "deployments"}
+
+// isCacheable reports whether the response of a call may be cached
+func isCacheable(call *Call) bool {
+	return call.Method == http.MethodGet &&
+		(strings.HasPrefix(call.Operation, "list-") || strings.HasPrefix(call.Operation, "get-"))
+}
+
+// isMutation reports whether a call changes state on the server
+func isMutation(call *Call) bool {
+	for _, prefix := range mutatingPrefixes {
+		if strings.HasPrefix(call.Operation, prefix) {
+			return true
+		}
+	}
+	return call.Method != http.MethodGet && call.Method != http.MethodHead
+}
+
+// cacheKeyFor identifies the cached response of a request by its URL and the
+// headers set for the call. Client-wide headers and the token are the same
+// for every call of a client and are not part of the key.
+func cacheKeyFor(u *url.URL, header http.Header) string {
+	if len(header) == 0 {
+		return u.String()
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(u.String())
+	for _, name := range names {
+		fmt.Fprintf(&b, "\n%s: %s", name, strings.Join(header[name], ", "))
+	}
+	return b.String()
+}
+
+// resourceKind returns the first segment of an API path, e.g. "applications"
+func resourceKind(path string) string {
+	kind, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return kind
+}
+
+type cacheEntry struct {
+	kind    string
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// responseCache is an in-memory TTL cache of response bodies keyed by
+// cacheKeyFor
+type responseCache struct {
+	cfg CacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	stats   CacheStats
+}
+
+// ttl returns the time to live of a resource kind
+func (rc *responseCache) ttl(kind string) time.Duration {
+	if ttl, ok := rc.cfg.TTL[kind]; ok {
+		return ttl
+	}
+	return rc.cfg.DefaultTTL
+}
+
+// lookup returns the entry for key and whether it is still fresh. Expired
+// entries are only returned when they can be revalidated with their ETag.
+func (rc *responseCache) lookup(key string) (*cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		rc.stats.Misses++
+		return nil, false
+	}
+	if time.Now().Before(entry.expires) {
+		rc.stats.Hits++
+		return entry, true
+	}
+
+	rc.stats.Misses++
+	if rc.cfg.ETags && entry.etag != "" {
+		return entry, false
+	}
+	delete(rc.entries, key)
+	return nil, false
+}
+
+// store caches a response body for key
+func (rc *responseCache) store(key, kind string, body []byte, etag string) {
+	ttl := rc.ttl(kind)
+	if ttl <= 0 {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries[key] = &cacheEntry{
+		kind:    kind,
+		body:    body,
+		etag:    etag,
+		expires: time.Now().Add(ttl),
+	}
+}
+
+// revalidated extends an entry after the server answered 304 Not Modified
+func (rc *responseCache) revalidated(key string, entry *cacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.stats.Revalidations++
+	if current, ok := rc.entries[key]; ok && current == entry {
+		entry.expires = time.Now().Add(rc.ttl(entry.kind))
+	}
+}
+
+// invalidate drops all entries of the given kinds
+func (rc *responseCache) invalidate(kinds ...string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for key, entry := range rc.entries {
+		for _, kind := range kinds {
+			if entry.kind == kind {
+				delete(rc.entries, key)
+				rc.stats.Invalidations++
+				break
+			}
+		}
+	}
+}
+
+// invalidateAll drops every entry
+func (rc *responseCache) invalidateAll() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.stats.Invalidations += int64(len(rc.entries))
+	rc.entries = make(map[string]*cacheEntry)
+}
+
+// snapshot returns a copy of the statistics
+func (rc *responseCache) snapshot() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	stats := rc.stats
+	stats.Entries = len(rc.entries)
+	return stats
+}
@@ -0,0 +1,260 @@
+package cagc
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingServer answers every request with body and counts the requests per
+// path
+type countingServer struct {
+	mu     sync.Mutex
+	hits   map[string]int
+	etag   string
+	body   string
+	saw304 int
+}
+
+func newCountingServer(body string) *countingServer {
+	return &countingServer{hits: make(map[string]int), body: body}
+}
+
+func (s *countingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hits[r.Method+" "+r.URL.Path]++
+
+	if s.etag != "" {
+		if r.Header.Get("If-None-Match") == s.etag {
+			s.saw304++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", s.etag)
+	}
+	w.Write([]byte(s.body))
+}
+
+func (s *countingServer) count(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[key]
+}
+
+func TestCacheHitsAndTTL(t *testing.T) {
+	srv := newCountingServer(`[]`)
+	c := newTestClient(t, srv.ServeHTTP, WithCache(CacheConfig{
+		DefaultTTL: time.Minute,
+		TTL:        map[string]time.Duration{"servers": 0},
+	}))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		c.ListApplications(ctx)
+		c.ListServers(ctx)
+	}
+
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"GET /api/v1/applications", 1},
+		{"GET /api/v1/servers", 3},
+	}
+	for _, tt := range tests {
+		if got := srv.count(tt.key); got != tt.want {
+			t.Errorf("%s: server saw %d requests, want %d", tt.key, got, tt.want)
+		}
+	}
+
+	stats := c.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("got stats %+v, want 2 hits, 1 miss and 1 entry", stats)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	srv := newCountingServer(`[]`)
+	c := newTestClient(t, srv.ServeHTTP, WithCache(CacheConfig{DefaultTTL: 20 * time.Millisecond}))
+	ctx := context.Background()
+
+	c.ListApplications(ctx)
+	time.Sleep(30 * time.Millisecond)
+	c.ListApplications(ctx)
+	if got := srv.count("GET /api/v1/applications"); got != 2 {
+		t.Errorf("server saw %d requests, want 2 after the TTL expired", got)
+	}
+}
+
+func TestCacheInvalidatesOnMutation(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(context.Context, *Client) error
+		read     func(context.Context, *Client) error
+		readPath string
+		body     string
+	}{
+		{
+			name: "own kind",
+			mutate: func(ctx context.Context, c *Client) error {
+				_, err := c.UpdateApplication(ctx, "app", Application{})
+				return err
+			},
+			read: func(ctx context.Context, c *Client) error {
+				_, err := c.GetApplication(ctx, "app")
+				return err
+			},
+			readPath: "/api/v1/applications/app",
+			body:     `{}`,
+		},
+		{
+			name: "aggregated resources",
+			mutate: func(ctx context.Context, c *Client) error {
+				_, err := c.DeleteDatabase(ctx, "db", false, false, false, false)
+				return err
+			},
+			read: func(ctx context.Context, c *Client) error {
+				_, err := c.ListResources(ctx)
+				return err
+			},
+			readPath: "/api/v1/resources",
+			body:     `[]`,
+		},
+		{
+			name: "server resources",
+			mutate: func(ctx context.Context, c *Client) error {
+				_, err := c.CreatePublicApplication(ctx, Application{})
+				return err
+			},
+			read: func(ctx context.Context, c *Client) error {
+				_, err := c.GetServerResources(ctx, "srv")
+				return err
+			},
+			readPath: "/api/v1/servers/srv/resources",
+			body:     `[]`,
+		},
+		{
+			name: "project environment",
+			mutate: func(ctx context.Context, c *Client) error {
+				_, err := c.RestartService(ctx, "svc")
+				return err
+			},
+			read: func(ctx context.Context, c *Client) error {
+				_, err := c.GetEnvironment(ctx, "proj", "production")
+				return err
+			},
+			readPath: "/api/v1/projects/proj/production",
+			body:     `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			reads := 0
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet && r.URL.Path == tt.readPath {
+					mu.Lock()
+					reads++
+					mu.Unlock()
+					w.Write([]byte(tt.body))
+					return
+				}
+				w.Write([]byte(`{}`))
+			}, WithCache(CacheConfig{DefaultTTL: time.Minute}))
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				if err := tt.read(ctx, c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tt.mutate(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.read(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if reads != 2 {
+				t.Errorf("server saw %d reads, want one before and one after the mutation", reads)
+			}
+		})
+	}
+}
+
+func TestCacheKeyIncludesCallHeaders(t *testing.T) {
+	srv := newCountingServer(`[]`)
+	c := newTestClient(t, srv.ServeHTTP, WithCache(CacheConfig{DefaultTTL: time.Minute}))
+	ctx := context.Background()
+
+	c.ListApplications(ctx, WithHeader("X-Team", "a"))
+	c.ListApplications(ctx, WithHeader("X-Team", "b"))
+	c.ListApplications(ctx, WithHeader("X-Team", "a"))
+	c.ListApplications(ctx)
+
+	if got := srv.count("GET /api/v1/applications"); got != 3 {
+		t.Errorf("server saw %d requests, want one per distinct header", got)
+	}
+}
+
+func TestCacheETagRevalidation(t *testing.T) {
+	srv := newCountingServer(`[{"uuid":"app"}]`)
+	srv.etag = `"v1"`
+	c := newTestClient(t, srv.ServeHTTP, WithCache(CacheConfig{DefaultTTL: 10 * time.Millisecond, ETags: true}))
+	ctx := context.Background()
+
+	if _, err := c.ListApplications(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	apps, err := c.ListApplications(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].UUID != "app" {
+		t.Errorf("got %+v from the revalidated entry", apps)
+	}
+	if srv.saw304 != 1 {
+		t.Errorf("server answered %d conditional requests with 304, want 1", srv.saw304)
+	}
+	if stats := c.CacheStats(); stats.Revalidations != 1 {
+		t.Errorf("got %d revalidations, want 1", stats.Revalidations)
+	}
+
+	// The revalidated entry is fresh again
+	c.ListApplications(ctx)
+	if got := srv.count("GET /api/v1/applications"); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}
+
+func TestInvalidateCache(t *testing.T) {
+	srv := newCountingServer(`[]`)
+	c := newTestClient(t, srv.ServeHTTP, WithCache(CacheConfig{DefaultTTL: time.Minute}))
+	ctx := context.Background()
+
+	c.ListApplications(ctx)
+	c.ListServers(ctx)
+	c.InvalidateCache("servers")
+	c.ListApplications(ctx)
+	c.ListServers(ctx)
+
+	if got := srv.count("GET /api/v1/applications"); got != 1 {
+		t.Errorf("applications: server saw %d requests, want 1", got)
+	}
+	if got := srv.count("GET /api/v1/servers"); got != 2 {
+		t.Errorf("servers: server saw %d requests, want 2", got)
+	}
+
+	c.InvalidateCache()
+	if stats := c.CacheStats(); stats.Entries != 0 || stats.Invalidations != 3 {
+		t.Errorf("got stats %+v, want no entries and 3 invalidations", stats)
+	}
+}
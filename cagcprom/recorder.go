@@ -0,0 +1,91 @@
+// Package cagcprom provides a Prometheus implementation of cagc.MetricsRecorder
+package cagcprom
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/kerlexov/cagc"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes every metric registered by the recorder
+const Namespace = "cagc"
+
+// Recorder records cagc API calls as Prometheus metrics. The collectors are
+// exported so tests can assert on them with prometheus/testutil without
+// scraping a running Prometheus.
+type Recorder struct {
+	// Requests counts calls by operation, method, status class and status
+	// code, so that e.g. 401 responses can be told apart from other 4xx
+	Requests *prometheus.CounterVec
+	// Duration observes call latency in seconds by operation and method
+	Duration *prometheus.HistogramVec
+	// Retries counts retried attempts by operation
+	Retries *prometheus.CounterVec
+	// RequestBytes and ResponseBytes count body bytes by operation
+	RequestBytes  *prometheus.CounterVec
+	ResponseBytes *prometheus.CounterVec
+}
+
+var _ cagc.MetricsRecorder = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder and registers its collectors with reg.
+// Pass prometheus.DefaultRegisterer to expose them on the default handler,
+// or a fresh prometheus.NewRegistry() in tests.
+func NewRecorder(reg prometheus.Registerer) (*Recorder, error) {
+	r := &Recorder{
+		Requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "requests_total",
+			Help:      "Coolify API calls by operation, method, status class and status code.",
+		}, []string{"operation", "method", "status_class", "status_code"}),
+		Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Coolify API calls including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "method"}),
+		Retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "retries_total",
+			Help:      "Retried attempts of Coolify API calls.",
+		}, []string{"operation"}),
+		RequestBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "request_bytes_total",
+			Help:      "Request body bytes sent to the Coolify API.",
+		}, []string{"operation"}),
+		ResponseBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "response_bytes_total",
+			Help:      "Response body bytes received from the Coolify API.",
+		}, []string{"operation"}),
+	}
+
+	for _, c := range []prometheus.Collector{r.Requests, r.Duration, r.Retries, r.RequestBytes, r.ResponseBytes} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// RecordCall implements cagc.MetricsRecorder
+func (r *Recorder) RecordCall(_ context.Context, m cagc.CallMetrics) {
+	r.Requests.WithLabelValues(m.Operation, m.Method, m.StatusClass, statusCode(m.StatusCode)).Inc()
+	r.Duration.WithLabelValues(m.Operation, m.Method).Observe(m.Duration.Seconds())
+	if m.Retries > 0 {
+		r.Retries.WithLabelValues(m.Operation).Add(float64(m.Retries))
+	}
+	r.RequestBytes.WithLabelValues(m.Operation).Add(float64(m.RequestBytes))
+	r.ResponseBytes.WithLabelValues(m.Operation).Add(float64(m.ResponseBytes))
+}
+
+// statusCode formats the status code label, empty when no response arrived
+func statusCode(code int) string {
+	if code == 0 {
+		return ""
+	}
+	return strconv.Itoa(code)
+}
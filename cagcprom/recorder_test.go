@@ -0,0 +1,99 @@
+package cagcprom
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kerlexov/cagc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecorder(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	recorder, err := NewRecorder(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := cagc.NewClient(srv.URL, "token", cagc.WithMetrics(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, code := range []int{http.StatusOK, http.StatusOK, http.StatusUnauthorized, http.StatusNotFound} {
+		status = code
+		client.ListServers(ctx)
+	}
+
+	tests := []struct {
+		class, code string
+		want        float64
+	}{
+		{"2xx", "200", 2},
+		{"4xx", "401", 1},
+		{"4xx", "404", 1},
+	}
+	for _, tt := range tests {
+		got := testutil.ToFloat64(recorder.Requests.WithLabelValues("list-servers", http.MethodGet, tt.class, tt.code))
+		if got != tt.want {
+			t.Errorf("requests_total{status_code=%q} = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	if got := testutil.ToFloat64(recorder.ResponseBytes.WithLabelValues("list-servers")); got != 8 {
+		t.Errorf("response_bytes_total = %v, want 8", got)
+	}
+	if got := testutil.CollectAndCount(recorder.Duration); got != 1 {
+		t.Errorf("request_duration_seconds has %d series, want 1", got)
+	}
+
+	expected := `
+# HELP cagc_requests_total Coolify API calls by operation, method, status class and status code.
+# TYPE cagc_requests_total counter
+cagc_requests_total{method="GET",operation="list-servers",status_class="2xx",status_code="200"} 2
+cagc_requests_total{method="GET",operation="list-servers",status_class="4xx",status_code="401"} 1
+cagc_requests_total{method="GET",operation="list-servers",status_class="4xx",status_code="404"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "cagc_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecorderRetries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	recorder, err := NewRecorder(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder.RecordCall(context.Background(), cagc.CallMetrics{Operation: "get-server-by-uuid", Method: http.MethodGet, StatusClass: "error", Retries: 2})
+	recorder.RecordCall(context.Background(), cagc.CallMetrics{Operation: "get-server-by-uuid", Method: http.MethodGet, StatusCode: 200, StatusClass: "2xx"})
+
+	if got := testutil.ToFloat64(recorder.Retries.WithLabelValues("get-server-by-uuid")); got != 2 {
+		t.Errorf("retries_total = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(recorder.Requests.WithLabelValues("get-server-by-uuid", http.MethodGet, "error", "")); got != 1 {
+		t.Errorf("requests_total without a response = %v, want 1", got)
+	}
+}
+
+func TestNewRecorderRegistersOnce(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := NewRecorder(reg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRecorder(reg); err == nil {
+		t.Error("expected an error registering the collectors twice")
+	}
+}
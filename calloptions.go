@@ -0,0 +1,126 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// idempotencyKeyHeader carries the key set with WithIdempotencyKey
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// CallOption configures a single API call. Every API method accepts call
+// options after its regular arguments.
+type CallOption interface {
+	applyCall(*callOptions) error
+}
+
+// SharedOption is accepted both by NewClient, where it applies to every call,
+// and by the API methods, where it applies to a single call
+type SharedOption interface {
+	Option
+	CallOption
+}
+
+// callOptions collects the call options of a single call
+type callOptions struct {
+	query       url.Values
+	header      http.Header
+	timeout     time.Duration
+	retryPolicy *RetryPolicy
+	noCache     bool
+	stream      func(io.Reader) error
+}
+
+// callOptionFunc adapts a plain function to the CallOption interface
+type callOptionFunc func(*callOptions) error
+
+func (f callOptionFunc) applyCall(o *callOptions) error {
+	return f(o)
+}
+
+// sharedOption implements SharedOption with one function per target
+type sharedOption struct {
+	client func(*Client) error
+	call   func(*callOptions) error
+}
+
+func (o sharedOption) apply(c *Client) error {
+	return o.client(c)
+}
+
+func (o sharedOption) applyCall(co *callOptions) error {
+	return o.call(co)
+}
+
+// WithQuery adds a query parameter to a call, e.g. one the server supports
+// before cagc has a typed argument for it. It replaces a parameter of the same
+// name set by the method; repeating WithQuery with one key sends every value.
+func WithQuery(key, value string) CallOption {
+	return callOptionFunc(func(o *callOptions) error {
+		if key == "" {
+			return errors.New("cagc: WithQuery requires a parameter name")
+		}
+		o.query.Add(key, value)
+		return nil
+	})
+}
+
+// WithIdempotencyKey sends key in the Idempotency-Key header, e.g. for a
+// proxy in front of Coolify that deduplicates requests. Coolify itself
+// ignores the header, so the call is not made retryable and the retry policy
+// applies as without it.
+func WithIdempotencyKey(key string) CallOption {
+	return callOptionFunc(func(o *callOptions) error {
+		if key == "" {
+			return errors.New("cagc: WithIdempotencyKey requires a key")
+		}
+		o.header.Set(idempotencyKeyHeader, key)
+		return nil
+	})
+}
+
+// WithNoCache sends a call to the server even when the response cache holds
+// a fresh entry for it, e.g. to poll for a status change. The response
+// replaces the cached entry.
+func WithNoCache() CallOption {
+	return callOptionFunc(func(o *callOptions) error {
+		o.noCache = true
+		return nil
+	})
+}
+
+// applyCallOptions applies opts to a call and returns the context to run it
+// with, along with a function that releases the context's resources
+func applyCallOptions(ctx context.Context, call *Call, opts []CallOption) (context.Context, context.CancelFunc, error) {
+	o := &callOptions{query: make(url.Values), header: make(http.Header)}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt.applyCall(o); err != nil {
+			return ctx, func() {}, err
+		}
+	}
+
+	for key, values := range o.query {
+		call.Query[key] = values
+	}
+	for key, values := range o.header {
+		call.Header[key] = values
+	}
+	call.noCache = o.noCache
+	call.stream = o.stream
+
+	if o.retryPolicy != nil {
+		ctx = ContextWithRetryPolicy(ctx, *o.retryPolicy)
+	}
+	if o.timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, o.timeout)
+		return ctx, cancel, nil
+	}
+	return ctx, func() {}, nil
+}
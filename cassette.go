@@ -0,0 +1,270 @@
+package cagc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// ErrCassetteMiss is returned in replay mode when a request has no matching
+// interaction in the cassette
+var ErrCassetteMiss = errors.New("cagc: no matching cassette interaction")
+
+// CassetteMode selects whether a cassette records or replays interactions
+type CassetteMode int
+
+const (
+	// CassetteReplay serves every request from the cassette and fails
+	// requests that have no matching interaction
+	CassetteReplay CassetteMode = iota
+	// CassetteRecord sends requests to the server and writes every
+	// interaction to the cassette, replacing its previous content
+	CassetteRecord
+	// CassetteAuto replays when the cassette file exists and records otherwise
+	CassetteAuto
+)
+
+// cassetteHeaders are the response headers kept in a cassette; everything
+// else, including cookies, is dropped
+var cassetteHeaders = []string{"Content-Type", "ETag", "Retry-After"}
+
+// WithCassette records the HTTP interactions of the client to a JSON file or
+// replays them from it, so tests can run against real Coolify responses
+// without a live instance. The bearer token is never written and secret
+// fields in request and response bodies are scrubbed.
+func WithCassette(path string, mode CassetteMode) Option {
+	return optionFunc(func(c *Client) error {
+		if mode == CassetteAuto {
+			mode = CassetteRecord
+			if _, err := os.Stat(path); err == nil {
+				mode = CassetteReplay
+			}
+		}
+
+		cassette := &cassette{path: path, mode: mode}
+		if mode == CassetteReplay {
+			if err := cassette.load(); err != nil {
+				return err
+			}
+		}
+		c.cassette = cassette
+		return nil
+	})
+}
+
+// cassetteFile is the on-disk format of a cassette
+type cassetteFile struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+
+	used bool
+}
+
+type recordedRequest struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+	// Text holds bodies that are not JSON, with anything resembling a
+	// credential redacted
+	Text string `json:"text,omitempty"`
+}
+
+// cassette records or replays HTTP interactions
+type cassette struct {
+	path string
+	mode CassetteMode
+
+	mu           sync.Mutex
+	interactions []*interaction
+}
+
+// load reads the interactions of a cassette file
+func (cs *cassette) load() error {
+	data, err := os.ReadFile(cs.path)
+	if err != nil {
+		return fmt.Errorf("cagc: loading cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("cagc: parsing cassette %s: %w", cs.path, err)
+	}
+	cs.interactions = file.Interactions
+	return nil
+}
+
+// save writes all recorded interactions to the cassette file atomically
+func (cs *cassette) save() error {
+	data, err := json.MarshalIndent(cassetteFile{Interactions: cs.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(cs.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("cagc: saving cassette: %w", err)
+		}
+	}
+	tmp := cs.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("cagc: saving cassette: %w", err)
+	}
+	return os.Rename(tmp, cs.path)
+}
+
+// roundTrip replays req from the cassette, or sends it with next and records
+// the interaction. In record mode, bodies larger than limit returns for their
+// status code are passed on without being recorded, so the caller handles
+// them as it would without a cassette.
+func (cs *cassette) roundTrip(req *http.Request, payload []byte, limit func(statusCode int) int64, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	recorded := recordedRequest{
+		Method: req.Method,
+		Path:   req.URL.EscapedPath(),
+		Query:  req.URL.RawQuery,
+	}
+	if len(payload) > 0 {
+		recorded.Body = scrubBody(payload)
+	}
+
+	if cs.mode == CassetteReplay {
+		return cs.replay(req, recorded)
+	}
+
+	resp, err := next(req)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := limit(resp.StatusCode)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	response := recordedResponse{StatusCode: resp.StatusCode, Header: make(map[string]string)}
+	for _, key := range cassetteHeaders {
+		if value := resp.Header.Get(key); value != "" {
+			response.Header[key] = value
+		}
+	}
+	if len(body) > 0 {
+		if json.Valid(body) {
+			response.Body = scrubBody(body)
+		} else {
+			response.Text = scrubText(string(body))
+		}
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.interactions = append(cs.interactions, &interaction{Request: recorded, Response: response})
+	if err := cs.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay returns the response of the first unused interaction matching req
+func (cs *cassette) replay(req *http.Request, recorded recordedRequest) (*http.Response, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for _, it := range cs.interactions {
+		if it.used || !it.Request.matches(recorded) {
+			continue
+		}
+		it.used = true
+
+		body := []byte(it.Response.Body)
+		if it.Response.Text != "" {
+			body = []byte(it.Response.Text)
+		}
+		header := make(http.Header)
+		for key, value := range it.Response.Header {
+			header.Set(key, value)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", it.Response.StatusCode, http.StatusText(it.Response.StatusCode)),
+			StatusCode:    it.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrCassetteMiss, req.Method, req.URL.RequestURI())
+}
+
+// matches reports whether two recorded requests are the same
+func (r recordedRequest) matches(other recordedRequest) bool {
+	return r.Method == other.Method &&
+		r.Path == other.Path &&
+		r.Query == other.Query &&
+		bytes.Equal(compactJSON(r.Body), compactJSON(other.Body))
+}
+
+// compactJSON strips insignificant whitespace, which differs between the
+// indented cassette file and live request bodies
+func compactJSON(data []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
+
+// textSecrets match credentials in bodies that are not JSON: private key
+// blocks, bearer tokens, user:password@ in URLs and secret=value assignments
+var textSecrets = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), redacted},
+	{regexp.MustCompile(`(?i)(bearer\s+)[^\s"']+`), "${1}" + redacted},
+	{regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/\s@]+@`), "${1}"},
+	{regexp.MustCompile(`(?i)\b([a-z0-9_]*(?:password|token|secret|api_key|license_key|private_key)[a-z0-9_]*["']?\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s,;&]+)`), "${1}" + redacted},
+}
+
+// scrubText redacts credentials from a body that is not JSON, e.g. a plain
+// text or HTML error page
+func scrubText(body string) string {
+	for _, secret := range textSecrets {
+		body = secret.re.ReplaceAllString(body, secret.repl)
+	}
+	return body
+}
+
+// scrubBody returns a JSON body with its secrets redacted, in a canonical
+// form so that recorded and live request bodies compare equal
+func scrubBody(body []byte) json.RawMessage {
+	return json.RawMessage(redactJSON(body))
+}
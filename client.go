@@ -0,0 +1,346 @@
+package cagc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultUserAgent is sent with every request unless overridden with WithUserAgent
+const defaultUserAgent = "cagc-go"
+
+// Client represents a cagc API client
+type Client struct {
+	BaseURL    *url.URL
+	httpClient *http.Client
+	tokens     TokenProvider
+
+	apiPrefix string
+	userAgent string
+	headers   http.Header
+	timeout   time.Duration
+	transport http.RoundTripper
+
+	middleware     []Middleware
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	logger         *slog.Logger
+	metrics        MetricsRecorder
+	cache          *responseCache
+	cassette       *cassette
+	drift          *driftDetector
+	breaker        *circuitBreaker
+	tls            *tlsSettings
+
+	maxResponseSize int64
+	dryRun          bool
+	plan            *Plan
+	retryPolicy     RetryPolicy
+	limiter         *rateLimiter
+	inFlight        chan struct{}
+}
+
+// NewClient creates a new cagc API client. The base URL may be given with or
+// without the /api/v1 prefix. Options are applied in order after the defaults,
+// so NewClient(baseURL, token) keeps working as before.
+func NewClient(baseURL string, token string, opts ...Option) (*Client, error) {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return nil, fmt.Errorf("cagc: base URL %q must include a scheme and host", baseURL)
+	}
+
+	c := &Client{
+		BaseURL:    parsedURL,
+		httpClient: &http.Client{},
+		tokens:     StaticToken(token),
+		userAgent:  defaultUserAgent,
+		headers:    make(http.Header),
+		apiPrefix:  DefaultAPIPrefix,
+
+		maxResponseSize: DefaultMaxResponseSize,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt.apply(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.timeout > 0 {
+		c.httpClient.Timeout = c.timeout
+	}
+	if c.transport != nil {
+		c.httpClient.Transport = c.transport
+	}
+	if err := c.applyTLS(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// doRequest performs an API call through the middleware chain and decodes the
+// response into v if provided. The path may carry an encoded query string.
+func (c *Client) doRequest(ctx context.Context, operation, method, path string, body interface{}, v interface{}, opts ...CallOption) error {
+	_, err := c.do(ctx, Request{Operation: operation, Method: method, Path: path, Body: body}, v, opts...)
+	return err
+}
+
+// execute sends a call to the API, or answers it from the cache, and decodes
+// the response into call.Result
+func (c *Client) execute(ctx context.Context, call *Call) error {
+	path := call.Path
+	if len(call.Query) > 0 {
+		path += "?" + call.Query.Encode()
+	}
+	u, err := c.resolve(path)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	if call.Body != nil {
+		payload, err = json.Marshal(call.Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if isMutation(call) {
+		if dryRun, plan := c.dryRunFor(ctx); dryRun {
+			return c.planCall(ctx, call, u, payload, plan)
+		}
+	}
+
+	kind := resourceKind(call.Path)
+	cacheKey := ""
+	var stale *cacheEntry
+	if c.cache != nil && call.stream == nil && isCacheable(call) && c.cache.ttl(kind) > 0 {
+		cacheKey = cacheKeyFor(u, call.Header)
+		if !call.noCache {
+			entry, fresh := c.cache.lookup(cacheKey)
+			if fresh {
+				call.StatusCode = http.StatusOK
+				return decodeResult(call, entry.body)
+			}
+			if entry != nil {
+				stale = entry
+				call.Header.Set("If-None-Match", entry.etag)
+			}
+		}
+	}
+
+	resp, body, err := c.sendWithRetries(ctx, call, u, payload)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && stale != nil {
+		c.cache.revalidated(cacheKey, stale)
+		return decodeResult(call, stale.body)
+	}
+	if c.cache != nil && isMutation(call) {
+		c.cache.invalidate(append([]string{kind}, dependentKinds...)...)
+	}
+	if err := decodeResult(call, body); err != nil {
+		return err
+	}
+	c.detectDrift(ctx, call)
+	if cacheKey != "" {
+		c.cache.store(cacheKey, kind, body, resp.Header.Get("ETag"))
+	}
+	return nil
+}
+
+// sendWithRetries sends a call, retrying transient failures according to the
+// applicable RetryPolicy and refreshing the token once after a 401 or a 400
+// rejecting the token. Non-2xx responses other than 304 to a conditional
+// request become an *APIError.
+func (c *Client) sendWithRetries(ctx context.Context, call *Call, u *url.URL, payload []byte) (*http.Response, []byte, error) {
+	policy := c.retryPolicyFor(ctx)
+	retryable := policy.allows(call)
+	conditional := call.Header.Get("If-None-Match") != ""
+	tokenRefreshed := false
+
+	for attempt := 1; ; attempt++ {
+		call.Attempts = attempt
+		canRetry := retryable && attempt < policy.MaxAttempts
+
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, body, err := c.send(ctx, call, u, token, payload)
+		if err != nil {
+			if call.streamed || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrResponseTooLarge) ||
+				errors.Is(err, ErrTLSVerification) || errors.Is(err, ErrCassetteMiss) {
+				return nil, nil, err
+			}
+			if canRetry && ctx.Err() == nil && sleepForRetry(ctx, policy.backoff(attempt, nil)) {
+				continue
+			}
+			if attempt > 1 {
+				return nil, nil, fmt.Errorf("cagc: %s %s failed after %d attempts: %w", call.Method, u.Path, attempt, err)
+			}
+			return nil, nil, err
+		}
+		call.StatusCode = resp.StatusCode
+		call.responseHeader = resp.Header
+
+		if resp.StatusCode == http.StatusNotModified && conditional {
+			return resp, body, nil
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			apiErr := newAPIError(call.Method, u.Path, resp.StatusCode, body)
+			apiErr.Attempts = attempt
+			apiErr.Truncated = call.bodyTruncated
+			if !tokenRefreshed && errors.Is(apiErr, ErrUnauthorized) {
+				// The token may have been rotated; retry once if a fresh one differs
+				tokenRefreshed = true
+				c.tokens.Invalidate()
+				if fresh, err := c.tokens.Token(ctx); err == nil && fresh != token {
+					continue
+				}
+			}
+			if canRetry && policy.retriesStatus(resp.StatusCode) && sleepForRetry(ctx, policy.backoff(attempt, resp.Header)) {
+				continue
+			}
+			return nil, nil, apiErr
+		}
+
+		return resp, body, nil
+	}
+}
+
+// decodeResult keeps the raw response body of a call and decodes it into
+// call.Result, if the call wants it
+func decodeResult(call *Call, body []byte) error {
+	call.responseBody = body
+	if call.Result == nil {
+		return nil
+	}
+	return json.Unmarshal(body, call.Result)
+}
+
+// send performs a single attempt of a call, waiting for the rate limiter and
+// an in-flight slot first, and returns the response with its body fully read
+func (c *Client) send(ctx context.Context, call *Call, u *url.URL, token string, payload []byte) (*http.Response, []byte, error) {
+	var bodyReader io.Reader
+	if payload != nil {
+		bodyReader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, call.Method, u.String(), bodyReader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	for key, values := range call.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	c.injectTraceContext(ctx, req.Header)
+
+	probe := false
+	if c.breaker != nil {
+		if probe, err = c.breaker.allow(); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", err, u.Host)
+		}
+	}
+
+	release, err := c.acquire(ctx)
+	if err != nil {
+		if c.breaker != nil {
+			c.breaker.done(probe, nil, err, true)
+		}
+		return nil, nil, err
+	}
+	defer release()
+
+	c.logRequest(ctx, call, u, payload)
+	call.requestBytes += int64(len(payload))
+	start := time.Now()
+
+	resp, err := c.roundTrip(req, payload)
+	if c.breaker != nil {
+		// A cassette miss never reached a server and says nothing about its health
+		c.breaker.done(probe, resp, err, ctx.Err() != nil || errors.Is(err, ErrCassetteMiss))
+	}
+	if err != nil {
+		err = classifyTLSError(u.Host, err)
+		c.logFailure(ctx, call, u, time.Since(start), err)
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if c.limiter != nil {
+		c.limiter.observe(resp.StatusCode, resp.Header)
+	}
+
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if success && call.stream != nil {
+		counter := &countingReader{r: resp.Body}
+		call.streamed = true
+		err := call.stream(counter)
+		call.responseBytes += counter.n
+		if err != nil {
+			c.logFailure(ctx, call, u, time.Since(start), err)
+			return nil, nil, err
+		}
+		c.logResponse(ctx, call, u, resp.StatusCode, time.Since(start), nil)
+		return resp, nil, nil
+	}
+
+	limit := c.bodyLimit(resp.StatusCode)
+	body, truncated, err := readLimited(resp.Body, limit)
+	if err != nil {
+		err = fmt.Errorf("cagc: reading response body: %w", err)
+		c.logFailure(ctx, call, u, time.Since(start), err)
+		return nil, nil, err
+	}
+	call.responseBytes += int64(len(body))
+	if truncated && success {
+		err := &ResponseTooLargeError{Method: call.Method, Path: u.Path, Limit: limit}
+		c.logFailure(ctx, call, u, time.Since(start), err)
+		return nil, nil, err
+	}
+	call.bodyTruncated = truncated
+
+	c.logResponse(ctx, call, u, resp.StatusCode, time.Since(start), body)
+	return resp, body, nil
+}
+
+// roundTrip sends a request over the HTTP client, or through the cassette
+// when one is configured
+func (c *Client) roundTrip(req *http.Request, payload []byte) (*http.Response, error) {
+	if c.cassette != nil {
+		return c.cassette.roundTrip(req, payload, c.bodyLimit, c.httpClient.Do)
+	}
+	return c.httpClient.Do(req)
+}
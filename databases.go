@@ -5,76 +5,109 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // ListDatabases lists all databases
-func (c *Client) ListDatabases(ctx context.Context) ([]Database, error) {
+func (c *Client) ListDatabases(ctx context.Context, opts ...CallOption) ([]Database, error) {
 	var databases []Database
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/databases", nil, &databases)
+	err := c.doRequest(ctx, "list-databases", http.MethodGet, apiPath("databases"), nil, &databases, opts...)
 	return databases, err
 }
 
 // GetDatabase gets a database by UUID
-func (c *Client) GetDatabase(ctx context.Context, uuid string) (*Database, error) {
-	path := fmt.Sprintf("/api/v1/databases/%s", uuid)
+func (c *Client) GetDatabase(ctx context.Context, uuid string, opts ...CallOption) (*Database, error) {
+	path := apiPath("databases", uuid)
 	var database Database
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &database)
+	err := c.doRequest(ctx, "get-database-by-uuid", http.MethodGet, path, nil, &database, opts...)
 	return &database, err
 }
 
 // CreatePostgresDatabase creates a new PostgreSQL database
-func (c *Client) CreatePostgresDatabase(ctx context.Context, db Database) (*CreateResponse, error) {
-	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/databases/postgresql", db, &response)
-	return &response, err
+func (c *Client) CreatePostgresDatabase(ctx context.Context, req PostgresCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "postgresql", req.DatabaseCreateFields, req, opts...)
 }
 
 // CreateClickhouseDatabase creates a new Clickhouse database
-func (c *Client) CreateClickhouseDatabase(ctx context.Context, db Database) (*CreateResponse, error) {
-	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/databases/clickhouse", db, &response)
-	return &response, err
+func (c *Client) CreateClickhouseDatabase(ctx context.Context, req ClickhouseCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "clickhouse", req.DatabaseCreateFields, req, opts...)
 }
 
 // CreateDragonflyDatabase creates a new DragonFly database
-func (c *Client) CreateDragonflyDatabase(ctx context.Context, db Database) (*CreateResponse, error) {
-	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/databases/dragonfly", db, &response)
-	return &response, err
+func (c *Client) CreateDragonflyDatabase(ctx context.Context, req DragonflyCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "dragonfly", req.DatabaseCreateFields, req, opts...)
 }
 
 // CreateRedisDatabase creates a new Redis database
-func (c *Client) CreateRedisDatabase(ctx context.Context, db Database) (*CreateResponse, error) {
-	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/databases/redis", db, &response)
-	return &response, err
+func (c *Client) CreateRedisDatabase(ctx context.Context, req RedisCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "redis", req.DatabaseCreateFields, req, opts...)
 }
 
 // CreateKeyDBDatabase creates a new KeyDB database
-func (c *Client) CreateKeyDBDatabase(ctx context.Context, db Database) (*CreateResponse, error) {
-	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/databases/keydb", db, &response)
-	return &response, err
+func (c *Client) CreateKeyDBDatabase(ctx context.Context, req KeyDBCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "keydb", req.DatabaseCreateFields, req, opts...)
 }
 
 // CreateMariaDBDatabase creates a new MariaDB database
-func (c *Client) CreateMariaDBDatabase(ctx context.Context, db Database) (*CreateResponse, error) {
+func (c *Client) CreateMariaDBDatabase(ctx context.Context, req MariaDBCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "mariadb", req.DatabaseCreateFields, req, opts...)
+}
+
+// CreateMySQLDatabase creates a new MySQL database
+func (c *Client) CreateMySQLDatabase(ctx context.Context, req MySQLCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "mysql", req.DatabaseCreateFields, req, opts...)
+}
+
+// CreateMongoDBDatabase creates a new MongoDB database
+func (c *Client) CreateMongoDBDatabase(ctx context.Context, req MongoDBCreateRequest, opts ...CallOption) (*CreateResponse, error) {
+	return c.createDatabase(ctx, "mongodb", req.DatabaseCreateFields, req, opts...)
+}
+
+// createDatabase validates the common fields of a create request and posts
+// it to the endpoint of the engine
+func (c *Client) createDatabase(ctx context.Context, engine string, fields DatabaseCreateFields, req interface{}, opts ...CallOption) (*CreateResponse, error) {
+	if err := fields.Validate(); err != nil {
+		return nil, err
+	}
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/databases/mariadb", db, &response)
+	err := c.doRequest(ctx, "create-database-"+engine, http.MethodPost, apiPath("databases", engine), req, &response, opts...)
 	return &response, err
 }
 
+// Validate checks the fields the API requires for every database engine. The
+// returned *ValidationError matches ErrValidation.
+func (f DatabaseCreateFields) Validate() error {
+	verr := &ValidationError{}
+	if strings.TrimSpace(f.ServerUUID) == "" {
+		verr.add("server_uuid", "is required")
+	}
+	if strings.TrimSpace(f.ProjectUUID) == "" {
+		verr.add("project_uuid", "is required")
+	}
+	if strings.TrimSpace(f.EnvironmentName) == "" && strings.TrimSpace(f.EnvironmentUUID) == "" {
+		verr.add("environment_name", "is required unless environment_uuid is set")
+	}
+	switch {
+	case f.PublicPort < 0 || f.PublicPort > 65535:
+		verr.add("public_port", "must be between 1 and 65535")
+	case f.IsPublic && f.PublicPort == 0:
+		verr.add("public_port", "is required when is_public is set")
+	}
+	return verr.errOrNil()
+}
+
 // UpdateDatabase updates an existing database
-func (c *Client) UpdateDatabase(ctx context.Context, uuid string, db Database) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/databases/%s", uuid)
+func (c *Client) UpdateDatabase(ctx context.Context, uuid string, db Database, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("databases", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, db, &response)
+	err := c.doRequest(ctx, "update-database-by-uuid", http.MethodPatch, path, db, &response, opts...)
 	return &response, err
 }
 
 // DeleteDatabase deletes a database
-func (c *Client) DeleteDatabase(ctx context.Context, uuid string, deleteConfigurations, deleteVolumes, dockerCleanup, deleteConnectedNetworks bool) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/databases/%s", uuid)
+func (c *Client) DeleteDatabase(ctx context.Context, uuid string, deleteConfigurations, deleteVolumes, dockerCleanup, deleteConnectedNetworks bool, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("databases", uuid)
 	query := url.Values{}
 	query.Add("delete_configurations", fmt.Sprintf("%t", deleteConfigurations))
 	query.Add("delete_volumes", fmt.Sprintf("%t", deleteVolumes))
@@ -82,10 +115,71 @@ func (c *Client) DeleteDatabase(ctx context.Context, uuid string, deleteConfigur
 	query.Add("delete_connected_networks", fmt.Sprintf("%t", deleteConnectedNetworks))
 
 	if len(query) > 0 {
-		path = fmt.Sprintf("%s?%s", path, query.Encode())
+		path += "?" + query.Encode()
 	}
 
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-database-by-uuid", http.MethodDelete, path, nil, &response, opts...)
+	return &response, err
+}
+
+// StartDatabase starts a database
+func (c *Client) StartDatabase(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("databases", uuid, "start")
+	var response CreateResponse
+	err := c.doRequest(ctx, "start-database-by-uuid", http.MethodGet, path, nil, &response, opts...)
+	return &response, err
+}
+
+// StopDatabase stops a database
+func (c *Client) StopDatabase(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("databases", uuid, "stop")
+	var response CreateResponse
+	err := c.doRequest(ctx, "stop-database-by-uuid", http.MethodGet, path, nil, &response, opts...)
+	return &response, err
+}
+
+// RestartDatabase restarts a database
+func (c *Client) RestartDatabase(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("databases", uuid, "restart")
+	var response CreateResponse
+	err := c.doRequest(ctx, "restart-database-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
+
+// defaultWaitInterval is the polling interval of the Wait helpers when none is given
+const defaultWaitInterval = 2 * time.Second
+
+// IsRunning reports whether the database status is "running", with any health suffix
+func (d *Database) IsRunning() bool {
+	return d.Status == "running" || strings.HasPrefix(d.Status, "running:")
+}
+
+// WaitForDatabaseRunning polls a database every interval, 2 seconds if zero,
+// until its status reports it running, and returns it. Polling bypasses the
+// response cache. It gives up when ctx ends or a request fails; bound the
+// wait with a context deadline.
+func (c *Client) WaitForDatabaseRunning(ctx context.Context, uuid string, interval time.Duration, opts ...CallOption) (*Database, error) {
+	if interval <= 0 {
+		interval = defaultWaitInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	opts = append(opts[:len(opts):len(opts)], WithNoCache())
+
+	for {
+		database, err := c.GetDatabase(ctx, uuid, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if database.IsRunning() {
+			return database, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("cagc: waiting for database %s to run, last status %q: %w", uuid, database.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForDatabaseRunningBypassesCache(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.Write([]byte(`{"uuid":"db","status":"exited"}`))
+			return
+		}
+		w.Write([]byte(`{"uuid":"db","status":"running:healthy"}`))
+	}, WithCache(CacheConfig{DefaultTTL: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// Populate the cache with the stopped status
+	if _, err := c.GetDatabase(ctx, "db"); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := c.WaitForDatabaseRunning(ctx, "db", 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !db.IsRunning() {
+		t.Errorf("got status %q, want running", db.Status)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("server saw %d requests, want 3", got)
+	}
+
+	// The last poll refreshed the cached entry
+	db, err = c.GetDatabase(ctx, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !db.IsRunning() || atomic.LoadInt32(&requests) != 3 {
+		t.Errorf("cached status %q after %d requests, want the polled status from the cache", db.Status, atomic.LoadInt32(&requests))
+	}
+}
+
+func TestDatabaseIsRunning(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"running", true},
+		{"running:healthy", true},
+		{"running:unhealthy", true},
+		{"exited", false},
+		{"restarting", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := (&Database{Status: tt.status}).IsRunning(); got != tt.want {
+			t.Errorf("IsRunning() with status %q = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestCreateDatabaseValidation(t *testing.T) {
+	valid := DatabaseCreateFields{ServerUUID: "server", ProjectUUID: "project", EnvironmentName: "production"}
+
+	tests := []struct {
+		name   string
+		modify func(*DatabaseCreateFields)
+		field  string
+	}{
+		{"missing server_uuid", func(f *DatabaseCreateFields) { f.ServerUUID = "" }, "server_uuid"},
+		{"missing project_uuid", func(f *DatabaseCreateFields) { f.ProjectUUID = " " }, "project_uuid"},
+		{"missing environment_name", func(f *DatabaseCreateFields) { f.EnvironmentName = "" }, "environment_name"},
+		{"public without a port", func(f *DatabaseCreateFields) { f.IsPublic = true }, "public_port"},
+		{"port out of range", func(f *DatabaseCreateFields) { f.PublicPort = 70000 }, "public_port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Errorf("invalid request sent: %s %s", r.Method, r.URL.Path)
+			})
+			fields := valid
+			tt.modify(&fields)
+
+			_, err := c.CreatePostgresDatabase(context.Background(), PostgresCreateRequest{DatabaseCreateFields: fields})
+			var verr *ValidationError
+			if !errors.Is(err, ErrValidation) || !errors.As(err, &verr) {
+				t.Fatalf("got %v, want a *ValidationError", err)
+			}
+			if _, ok := verr.Errors[tt.field]; !ok || len(verr.Errors) != 1 {
+				t.Errorf("got errors %v, want one for %s", verr.Errors, tt.field)
+			}
+		})
+	}
+
+	public := valid
+	public.IsPublic = true
+	public.PublicPort = 5432
+	if err := public.Validate(); err != nil {
+		t.Errorf("Validate() with a public port = %v", err)
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
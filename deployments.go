@@ -2,29 +2,47 @@ package cagc
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
+	"net/url"
 )
 
 // ListDeployments lists all currently running deployments
-func (c *Client) ListDeployments(ctx context.Context) ([]Deployment, error) {
+func (c *Client) ListDeployments(ctx context.Context, opts ...CallOption) ([]Deployment, error) {
 	var deployments []Deployment
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/deployments", nil, &deployments)
+	err := c.doRequest(ctx, "list-deployments", http.MethodGet, apiPath("deployments"), nil, &deployments, opts...)
 	return deployments, err
 }
 
 // GetDeployment gets a deployment by UUID
-func (c *Client) GetDeployment(ctx context.Context, uuid string) (*Deployment, error) {
-	path := fmt.Sprintf("/api/v1/deployments/%s", uuid)
+func (c *Client) GetDeployment(ctx context.Context, uuid string, opts ...CallOption) (*Deployment, error) {
+	path := apiPath("deployments", uuid)
 	var deployment Deployment
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &deployment)
+	err := c.doRequest(ctx, "get-deployment-by-uuid", http.MethodGet, path, nil, &deployment, opts...)
 	return &deployment, err
 }
 
-// DeployByTagOrUUID deploys by tag or UUID
-func (c *Client) DeployByTagOrUUID(ctx context.Context, tagOrUUID string) (*DeploymentResponse, error) {
-	path := fmt.Sprintf("/api/v1/deployments/%s", tagOrUUID)
-	var response DeploymentResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+// DeployByTagOrUUID deploys the resources with the given tags or UUIDs. Both
+// accept a comma separated list and at least one must be set. force rebuilds
+// without the build cache.
+func (c *Client) DeployByTagOrUUID(ctx context.Context, tag, uuid string, force bool, opts ...CallOption) (*DeployResponse, error) {
+	if tag == "" && uuid == "" {
+		return nil, errors.New("cagc: DeployByTagOrUUID requires a tag or a UUID")
+	}
+
+	query := url.Values{}
+	if tag != "" {
+		query.Add("tag", tag)
+	}
+	if uuid != "" {
+		query.Add("uuid", uuid)
+	}
+	if force {
+		query.Add("force", "true")
+	}
+	path := apiPath("deploy") + "?" + query.Encode()
+
+	var response DeployResponse
+	err := c.doRequest(ctx, "deploy-by-tag-or-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
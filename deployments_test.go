@@ -0,0 +1,50 @@
+package cagc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestDeployByTagOrUUID(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		uuid      string
+		force     bool
+		wantQuery string
+	}{
+		{"by tag", "production", "", false, "tag=production"},
+		{"by uuid", "", "app1,app2", false, "uuid=app1%2Capp2"},
+		{"forced", "", "app1", true, "force=true&uuid=app1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/api/v1/deploy" {
+					t.Errorf("got %s %s, want GET /api/v1/deploy", r.Method, r.URL.Path)
+				}
+				if r.URL.RawQuery != tt.wantQuery {
+					t.Errorf("got query %q, want %q", r.URL.RawQuery, tt.wantQuery)
+				}
+				w.Write([]byte(`{"deployments":[{"message":"queued","resource_uuid":"app1","deployment_uuid":"dep1"}]}`))
+			})
+
+			resp, err := c.DeployByTagOrUUID(context.Background(), tt.tag, tt.uuid, tt.force)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Deployments) != 1 || resp.Deployments[0].DeploymentUUID != "dep1" || resp.Deployments[0].ResourceUUID != "app1" {
+				t.Errorf("got %+v", resp)
+			}
+		})
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent without a tag or UUID")
+	})
+	if _, err := c.DeployByTagOrUUID(context.Background(), "", "", false); err == nil {
+		t.Error("expected an error without a tag or UUID")
+	}
+}
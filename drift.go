@@ -0,0 +1,220 @@
+package cagc
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SchemaDrift describes response fields that the server returned but that
+// have no counterpart in the Go type they were decoded into
+type SchemaDrift struct {
+	// Operation is the OpenAPI operationId of the call
+	Operation string
+	// Type is the Go type of the result, e.g. "[]cagc.Application"
+	Type string
+	// Fields are the unknown fields as JSON paths, e.g. "settings.is_new_flag".
+	// "[]" stands for any element of an array and "*" for any value of an object.
+	Fields []string
+}
+
+// DriftHandler receives the schema drift detected in strict decoding mode
+type DriftHandler func(ctx context.Context, drift SchemaDrift)
+
+// WithStrictDecoding compares every decoded response with its Go type and
+// reports fields the server returned that the type does not know about.
+// Drift never fails a call. Each field is reported once per client and type,
+// to handler or, when handler is nil, as a warning on the logger set with
+// WithLogger or slog.Default.
+func WithStrictDecoding(handler DriftHandler) Option {
+	return optionFunc(func(c *Client) error {
+		c.drift = &driftDetector{handler: handler}
+		return nil
+	})
+}
+
+// driftDetector finds and reports unknown response fields
+type driftDetector struct {
+	handler DriftHandler
+
+	mu       sync.Mutex
+	reported map[string]bool
+}
+
+// detectDrift checks the raw response body of a call against the type of
+// call.Result
+func (c *Client) detectDrift(ctx context.Context, call *Call) {
+	if c.drift == nil || call.Result == nil || len(call.responseBody) == 0 {
+		return
+	}
+	var data interface{}
+	if err := json.Unmarshal(call.responseBody, &data); err != nil {
+		return
+	}
+
+	t := reflect.TypeOf(call.Result)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var fields []string
+	unknownFields(data, t, "", &fields)
+
+	fields = c.drift.unreported(t.String(), fields)
+	if len(fields) == 0 {
+		return
+	}
+	drift := SchemaDrift{Operation: call.Operation, Type: t.String(), Fields: fields}
+
+	if c.drift.handler != nil {
+		c.drift.handler(ctx, drift)
+		return
+	}
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, slog.LevelWarn, "cagc: response fields missing from type",
+		slog.String("operation", drift.Operation),
+		slog.String("type", drift.Type),
+		slog.Any("fields", drift.Fields),
+	)
+}
+
+// unreported returns the sorted fields of typeName that were not reported
+// before and marks them as reported
+func (d *driftDetector) unreported(typeName string, fields []string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.reported == nil {
+		d.reported = make(map[string]bool)
+	}
+	var out []string
+	for _, field := range fields {
+		key := typeName + " " + field
+		if d.reported[key] {
+			continue
+		}
+		d.reported[key] = true
+		out = append(out, field)
+	}
+	sort.Strings(out)
+	return out
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields appends the JSON paths in data that t cannot hold
+func unknownFields(data interface{}, t reflect.Type, prefix string, out *[]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range object {
+			field, ok := lookupField(fields, key)
+			if !ok {
+				appendUnique(out, joinPath(prefix, key))
+				continue
+			}
+			unknownFields(value, field.Type, joinPath(prefix, key), out)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := data.([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range items {
+			unknownFields(item, t.Elem(), prefix+"[]", out)
+		}
+	case reflect.Map:
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, value := range object {
+			unknownFields(value, t.Elem(), joinPath(prefix, "*"), out)
+		}
+	}
+}
+
+// jsonFields returns the struct fields of t by JSON name, including the
+// fields of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, f := range jsonFields(embedded) {
+					if _, ok := fields[key]; !ok {
+						fields[key] = f
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// lookupField finds the field for a JSON key the way encoding/json does,
+// preferring an exact match over a case-insensitive one
+func lookupField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// joinPath appends a key to a JSON path
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// appendUnique appends path to out unless it is already present
+func appendUnique(out *[]string, path string) {
+	for _, existing := range *out {
+		if existing == path {
+			return
+		}
+	}
+	*out = append(*out, path)
+}
@@ -0,0 +1,124 @@
+package cagc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// PlannedRequest is a mutating call that was not sent because of dry-run mode
+type PlannedRequest struct {
+	// Operation is the OpenAPI operationId of the call
+	Operation string `json:"operation"`
+	// Method is the HTTP method
+	Method string `json:"method"`
+	// URL is the fully resolved request URL including the query string
+	URL string `json:"url"`
+	// Query holds the query parameters of the request
+	Query url.Values `json:"query,omitempty"`
+	// Body is the JSON request body, or nil. It is not redacted.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// String renders the request as "METHOD URL" followed by the body, if any
+func (p PlannedRequest) String() string {
+	if len(p.Body) == 0 {
+		return fmt.Sprintf("%s %s", p.Method, p.URL)
+	}
+	return fmt.Sprintf("%s %s %s", p.Method, p.URL, p.Body)
+}
+
+// Plan collects the requests planned in dry-run mode. It is safe for
+// concurrent use.
+type Plan struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// Requests returns the planned requests in the order they were made
+func (p *Plan) Requests() []PlannedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlannedRequest(nil), p.requests...)
+}
+
+func (p *Plan) add(r PlannedRequest) {
+	p.mu.Lock()
+	p.requests = append(p.requests, r)
+	p.mu.Unlock()
+}
+
+// WithDryRun stops every mutating call of the client (Create, Update, Delete,
+// Start, Stop, Restart, Execute and Deploy) from being sent. Each such call
+// is appended to plan, which may be nil, and returns a synthetic response
+// whose message describes the skipped request. Read-only calls are sent as usual.
+func WithDryRun(plan *Plan) Option {
+	return optionFunc(func(c *Client) error {
+		c.dryRun = true
+		c.plan = plan
+		return nil
+	})
+}
+
+type dryRunKey struct{}
+
+// ContextWithDryRun enables dry-run mode for the calls made with the returned
+// context. Planned requests are added to plan, or to the client's plan when
+// plan is nil.
+func ContextWithDryRun(ctx context.Context, plan *Plan) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dryRunSettings{plan: plan})
+}
+
+type dryRunSettings struct {
+	plan *Plan
+}
+
+// dryRunFor reports whether dry-run mode applies to ctx and which plan
+// collects the planned requests
+func (c *Client) dryRunFor(ctx context.Context) (bool, *Plan) {
+	if settings, ok := ctx.Value(dryRunKey{}).(dryRunSettings); ok {
+		if settings.plan != nil {
+			return true, settings.plan
+		}
+		return true, c.plan
+	}
+	return c.dryRun, c.plan
+}
+
+// planCall records a mutating call instead of sending it and answers it with
+// a synthetic response
+func (c *Client) planCall(ctx context.Context, call *Call, u *url.URL, payload []byte, plan *Plan) error {
+	planned := PlannedRequest{
+		Operation: call.Operation,
+		Method:    call.Method,
+		URL:       u.String(),
+		Body:      payload,
+	}
+	if len(call.Query) > 0 {
+		planned.Query = call.Query
+	}
+	if plan != nil {
+		plan.add(planned)
+	}
+
+	if c.debugEnabled(ctx) {
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "cagc: dry run",
+			slog.String("operation", call.Operation),
+			slog.String("method", call.Method),
+			slog.String("path", u.Path),
+		)
+	}
+
+	synthetic, err := json.Marshal(MessageResponse{
+		Message: fmt.Sprintf("Dry run: %s %s was not sent.", call.Method, u.Path),
+	})
+	if err != nil {
+		return err
+	}
+	call.StatusCode = http.StatusOK
+	return decodeResult(call, synthetic)
+}
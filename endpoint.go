@@ -0,0 +1,67 @@
+package cagc
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultAPIPrefix is the path under which Coolify serves its API
+const DefaultAPIPrefix = "/api/v1"
+
+// WithAPIPrefix overrides the path prefix of the API, for instances served
+// behind a reverse proxy that rewrites it. An empty prefix uses the base URL
+// as is.
+func WithAPIPrefix(prefix string) Option {
+	return optionFunc(func(c *Client) error {
+		c.apiPrefix = normalizePrefix(prefix)
+		return nil
+	})
+}
+
+// apiPath builds an API path relative to the API prefix from its segments,
+// escaping each segment so that UUIDs and names can never alter the route
+func apiPath(segments ...string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(segment))
+	}
+	return b.String()
+}
+
+// resolve returns the absolute URL of an API path built by apiPath, which may
+// carry an encoded query string. The base URL works with or without the API
+// prefix and with or without a trailing slash.
+func (c *Client) resolve(path string) (*url.URL, error) {
+	ref, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	basePath := strings.TrimRight(c.BaseURL.EscapedPath(), "/")
+	if c.apiPrefix != "" && !strings.HasSuffix(basePath, c.apiPrefix) {
+		basePath += c.apiPrefix
+	}
+	escapedPath := basePath + ref.EscapedPath()
+
+	unescapedPath, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	u := *c.BaseURL
+	u.Path = unescapedPath
+	u.RawPath = escapedPath
+	u.RawQuery = ref.RawQuery
+	u.Fragment = ""
+	return &u, nil
+}
+
+// normalizePrefix turns a prefix into the "/segment/segment" form
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
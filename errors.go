@@ -0,0 +1,188 @@
+package cagc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors for classifying API failures with errors.Is
+var (
+	// ErrNotFound matches 404 responses
+	ErrNotFound = errors.New("cagc: resource not found")
+	// ErrUnauthorized matches 401 responses and 400 responses rejecting the token
+	ErrUnauthorized = errors.New("cagc: unauthorized")
+	// ErrForbidden matches 403 responses
+	ErrForbidden = errors.New("cagc: forbidden")
+	// ErrValidation matches 400 and 422 responses caused by invalid input
+	ErrValidation = errors.New("cagc: validation failed")
+	// ErrRateLimited matches 429 responses
+	ErrRateLimited = errors.New("cagc: rate limited")
+)
+
+// maxErrorMessageLength caps the raw body shown in APIError.Error, e.g. for
+// HTML error pages of a reverse proxy
+const maxErrorMessageLength = 512
+
+// APIError is returned for every non-2xx response from the Coolify API
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Message is the "message" field of the response body, if any
+	Message string
+	// Errors holds per-field validation messages sent with 400 and 422 responses
+	Errors map[string][]string
+	// Method and Path identify the request that failed
+	Method string
+	Path   string
+	// Body is the raw response body, cut off after 64 KiB
+	Body []byte
+	// Truncated reports whether Body was cut off
+	Truncated bool
+	// Attempts is the number of attempts made, including retries
+	Attempts int
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cagc: %s %s: status %d", e.Method, e.Path, e.StatusCode)
+
+	message := e.Message
+	if message == "" && len(e.Body) > 0 {
+		message = strings.TrimSpace(string(e.Body))
+		if len(message) > maxErrorMessageLength {
+			message = strings.ToValidUTF8(message[:maxErrorMessageLength], "") + "..."
+		}
+	}
+	if message != "" {
+		fmt.Fprintf(&b, ": %s", message)
+	}
+
+	if len(e.Errors) > 0 {
+		fields := make([]string, 0, len(e.Errors))
+		for field := range e.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for i, field := range fields {
+			sep := ", "
+			if i == 0 {
+				sep = " ("
+			}
+			fmt.Fprintf(&b, "%s%s: %s", sep, field, strings.Join(e.Errors[field], "; "))
+		}
+		b.WriteString(")")
+	}
+
+	return b.String()
+}
+
+// Is reports whether the error matches one of the package sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized ||
+			(e.StatusCode == http.StatusBadRequest && e.isTokenError())
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity ||
+			(e.StatusCode == http.StatusBadRequest && !e.isTokenError())
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// isTokenError reports whether a 400 response complains about the bearer token
+// rather than the request payload
+func (e *APIError) isTokenError() bool {
+	return len(e.Errors) == 0 && strings.Contains(strings.ToLower(e.Message), "token")
+}
+
+// newAPIError builds an APIError from a failed response and its body
+func newAPIError(method, path string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Body:       body,
+	}
+
+	var payload struct {
+		Message string                     `json:"message"`
+		Errors  map[string]json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return apiErr
+	}
+
+	apiErr.Message = payload.Message
+	if len(payload.Errors) > 0 {
+		apiErr.Errors = make(map[string][]string, len(payload.Errors))
+		for field, raw := range payload.Errors {
+			// Coolify usually sends a list of messages per field, but a
+			// single string shows up on some endpoints
+			var messages []string
+			if err := json.Unmarshal(raw, &messages); err != nil {
+				var message string
+				if err := json.Unmarshal(raw, &message); err != nil {
+					message = string(raw)
+				}
+				messages = []string{message}
+			}
+			apiErr.Errors[field] = messages
+		}
+	}
+
+	return apiErr
+}
+
+// ValidationError is returned when a request fails client-side validation
+// before it is sent. It matches ErrValidation.
+type ValidationError struct {
+	// Errors holds the validation messages per JSON field
+	Errors map[string][]string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s: %s", field, strings.Join(e.Errors[field], "; "))
+	}
+	return fmt.Sprintf("cagc: validation failed (%s)", strings.Join(parts, ", "))
+}
+
+// Is makes errors.Is(err, ErrValidation) match
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// add records a validation message for a field
+func (e *ValidationError) add(field, message string) {
+	if e.Errors == nil {
+		e.Errors = make(map[string][]string)
+	}
+	e.Errors[field] = append(e.Errors[field], message)
+}
+
+// errOrNil returns e if it holds any message and nil otherwise
+func (e *ValidationError) errOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kerlexov/cagc"
 	"log"
 	"os"
+	"time"
 )
 
 func main() {
@@ -26,7 +27,7 @@ func main() {
 	}
 
 	// Create a new client
-	client, err := cagc.NewClient(serverUrl, token)
+	client, err := cagc.NewClient(serverUrl, token, cagc.WithTimeout(30*time.Second))
 	if err != nil {
 		log.Fatalf("Error creating client: %v", err)
 	}
@@ -59,7 +60,7 @@ func main() {
 	// Print server details
 	fmt.Printf("\nFound %d servers:\n", len(servers))
 	for _, server := range servers {
-		fmt.Printf("- UUID: %s, Name: %s, Status: %s\n", server.UUID, server.Name, server.Status)
+		fmt.Printf("- UUID: %s, Name: %s, IP: %s\n", server.UUID, server.Name, server.IP)
 	}
 
 	// List all services
@@ -71,7 +72,7 @@ func main() {
 	// Print service details
 	fmt.Printf("\nFound %d services:\n", len(services))
 	for _, service := range services {
-		fmt.Printf("- UUID: %s, Name: %s, Type: %s\n", service.UUID, service.Name, service.Type)
+		fmt.Printf("- UUID: %s, Name: %s, Type: %s\n", service.UUID, service.Name, service.ServiceType)
 	}
 
 	// List all projects
@@ -131,7 +132,7 @@ func main() {
 	// Print deployment details
 	fmt.Printf("\nFound %d deployments:\n", len(deployments))
 	for _, deployment := range deployments {
-		fmt.Printf("- UUID: %s, Status: %s\n", deployment.UUID, deployment.Status)
+		fmt.Printf("- UUID: %s, Status: %s\n", deployment.DeploymentUUID, deployment.Status)
 	}
 
 	// List all teams
@@ -149,15 +150,17 @@ func main() {
 	// Example: Create a new PostgreSQL database (commented out to prevent accidental creation)
 	/*
 		fmt.Println("\nCreating a PostgreSQL database...")
-		newDB := cagc.Database{
-			ProjectUUID:      "your-project-uuid",
-			ServerUUID:       "your-server-uuid",
-			EnvironmentName:  "development",
-			Name:             "example-postgres",
+		newDB := cagc.PostgresCreateRequest{
+			DatabaseCreateFields: cagc.DatabaseCreateFields{
+				ProjectUUID:     "your-project-uuid",
+				ServerUUID:      "your-server-uuid",
+				EnvironmentName: "development",
+				Name:            "example-postgres",
+				IsPublic:        false,
+			},
 			PostgresUser:     "postgres",
 			PostgresPassword: "secure-password",
 			PostgresDB:       "exampledb",
-			IsPublic:         false,
 		}
 
 		resp, err := client.CreatePostgresDatabase(context.Background(), newDB)
@@ -0,0 +1,181 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Fleet queries several Coolify instances, each through its own named Client
+type Fleet struct {
+	clients     map[string]*Client
+	names       []string
+	concurrency int
+}
+
+// FleetOption configures a Fleet
+type FleetOption func(*Fleet) error
+
+// WithFleetConcurrency limits how many instances a Fleet queries at the same
+// time. By default all instances are queried at once.
+func WithFleetConcurrency(n int) FleetOption {
+	return func(f *Fleet) error {
+		if n < 1 {
+			return errors.New("cagc: WithFleetConcurrency requires at least one instance")
+		}
+		f.concurrency = n
+		return nil
+	}
+}
+
+// NewFleet creates a Fleet from clients keyed by instance name, e.g. "eu",
+// "us" and "staging"
+func NewFleet(clients map[string]*Client, opts ...FleetOption) (*Fleet, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("cagc: NewFleet requires at least one client")
+	}
+
+	f := &Fleet{clients: make(map[string]*Client, len(clients))}
+	for name, client := range clients {
+		if client == nil {
+			return nil, fmt.Errorf("cagc: NewFleet: client %q is nil", name)
+		}
+		f.clients[name] = client
+		f.names = append(f.names, name)
+	}
+	sort.Strings(f.names)
+	f.concurrency = len(f.names)
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(f); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Names returns the instance names in sorted order
+func (f *Fleet) Names() []string {
+	return append([]string(nil), f.names...)
+}
+
+// Client returns the client of the named instance
+func (f *Fleet) Client(name string) (*Client, bool) {
+	client, ok := f.clients[name]
+	return client, ok
+}
+
+// FleetItem is a result tagged with the instance it came from
+type FleetItem[T any] struct {
+	Instance string
+	Item     T
+}
+
+// FleetError collects the errors of the instances that failed during a fleet
+// call. The results of the other instances are still returned alongside it.
+type FleetError struct {
+	// Errors holds the error of every failed instance by name
+	Errors map[string]error
+}
+
+// Error implements the error interface
+func (e *FleetError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s: %v", name, e.Errors[name])
+	}
+	return fmt.Sprintf("cagc: %d instance(s) failed: %s", len(names), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the instance errors so errors.Is and errors.As see them
+func (e *FleetError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// FanOut calls fn for every instance of the fleet, respecting the concurrency
+// limit, and returns the combined results ordered by instance name. If some
+// instances fail, the results of the others are returned with a *FleetError.
+// fn has the shape of a method expression such as (*Client).ListProjects and
+// receives opts on every call.
+func FanOut[T any](ctx context.Context, f *Fleet, fn func(client *Client, ctx context.Context, opts ...CallOption) ([]T, error), opts ...CallOption) ([]FleetItem[T], error) {
+	results := make([][]T, len(f.names))
+	errs := make([]error, len(f.names))
+	slots := make(chan struct{}, f.concurrency)
+
+	var wg sync.WaitGroup
+	for i, name := range f.names {
+		wg.Add(1)
+		go func(i int, client *Client) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+			results[i], errs[i] = fn(client, ctx, opts...)
+		}(i, f.clients[name])
+	}
+	wg.Wait()
+
+	var items []FleetItem[T]
+	var fleetErr *FleetError
+	for i, name := range f.names {
+		if errs[i] != nil {
+			if fleetErr == nil {
+				fleetErr = &FleetError{Errors: make(map[string]error)}
+			}
+			fleetErr.Errors[name] = errs[i]
+			continue
+		}
+		for _, item := range results[i] {
+			items = append(items, FleetItem[T]{Instance: name, Item: item})
+		}
+	}
+	if fleetErr != nil {
+		return items, fleetErr
+	}
+	return items, nil
+}
+
+// ListApplications lists the applications of every instance
+func (f *Fleet) ListApplications(ctx context.Context, opts ...CallOption) ([]FleetItem[Application], error) {
+	return FanOut(ctx, f, (*Client).ListApplications, opts...)
+}
+
+// ListServers lists the servers of every instance
+func (f *Fleet) ListServers(ctx context.Context, opts ...CallOption) ([]FleetItem[Server], error) {
+	return FanOut(ctx, f, (*Client).ListServers, opts...)
+}
+
+// ListDatabases lists the databases of every instance
+func (f *Fleet) ListDatabases(ctx context.Context, opts ...CallOption) ([]FleetItem[Database], error) {
+	return FanOut(ctx, f, (*Client).ListDatabases, opts...)
+}
+
+// ListServices lists the services of every instance
+func (f *Fleet) ListServices(ctx context.Context, opts ...CallOption) ([]FleetItem[Service], error) {
+	return FanOut(ctx, f, (*Client).ListServices, opts...)
+}
+
+// ListDeployments lists the running deployments of every instance
+func (f *Fleet) ListDeployments(ctx context.Context, opts ...CallOption) ([]FleetItem[Deployment], error) {
+	return FanOut(ctx, f, (*Client).ListDeployments, opts...)
+}
@@ -0,0 +1,84 @@
+package cagc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseSize is the largest successful response body a client
+// reads unless configured otherwise with WithMaxResponseSize
+const DefaultMaxResponseSize = 32 << 20
+
+// maxErrorBodySize is the largest part of an error response body kept in an
+// APIError; the rest is discarded
+const maxErrorBodySize = 64 << 10
+
+// ErrResponseTooLarge matches a *ResponseTooLargeError
+var ErrResponseTooLarge = errors.New("cagc: response too large")
+
+// ResponseTooLargeError is returned when a response body exceeds the maximum
+// response size of the client. Use a Stream method to read large lists.
+type ResponseTooLargeError struct {
+	// Method and Path identify the request
+	Method string
+	Path   string
+	// Limit is the maximum response size in bytes
+	Limit int64
+}
+
+// Error implements the error interface
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("cagc: %s %s: response body exceeds %d bytes", e.Method, e.Path, e.Limit)
+}
+
+// Is makes errors.Is(err, ErrResponseTooLarge) match
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// WithMaxResponseSize limits the size of successful response bodies the
+// client reads into memory. Larger responses fail with a
+// *ResponseTooLargeError. Streaming calls are not limited.
+func WithMaxResponseSize(n int64) Option {
+	return optionFunc(func(c *Client) error {
+		if n < 1 {
+			return errors.New("cagc: WithMaxResponseSize requires a positive size")
+		}
+		c.maxResponseSize = n
+		return nil
+	})
+}
+
+// bodyLimit returns the largest body the client reads for a response with
+// the given status code
+func (c *Client) bodyLimit(statusCode int) int64 {
+	if statusCode >= 200 && statusCode < 300 {
+		return c.maxResponseSize
+	}
+	return maxErrorBodySize
+}
+
+// readLimited reads up to limit bytes from r and reports whether there was more
+func readLimited(r io.Reader, limit int64) ([]byte, bool, error) {
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(body)) > limit {
+		return body[:limit], true, nil
+	}
+	return body, false, nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
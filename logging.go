@@ -0,0 +1,215 @@
+package cagc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redacted replaces secret values in logs
+const redacted = "[REDACTED]"
+
+// WithLogger logs every request and response at debug level: method, path,
+// status, latency, body size and the body itself with secrets redacted.
+// The bearer token is never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(c *Client) error {
+		if logger == nil {
+			return errors.New("cagc: WithLogger requires a non-nil logger")
+		}
+		c.logger = logger
+		return nil
+	})
+}
+
+// debugEnabled reports whether request logging is active for ctx
+func (c *Client) debugEnabled(ctx context.Context) bool {
+	return c.logger != nil && c.logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// logRequest logs an attempt before it is sent
+func (c *Client) logRequest(ctx context.Context, call *Call, u *url.URL, payload []byte) {
+	if !c.debugEnabled(ctx) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Method),
+		slog.String("path", u.Path),
+		slog.Int("attempt", call.Attempts),
+		slog.Int("body_size", len(payload)),
+	}
+	if len(u.RawQuery) > 0 {
+		attrs = append(attrs, slog.String("query", u.RawQuery))
+	}
+	if len(payload) > 0 {
+		attrs = append(attrs, slog.String("body", string(redactJSON(payload))))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "cagc: request", attrs...)
+}
+
+// logResponse logs the response of an attempt
+func (c *Client) logResponse(ctx context.Context, call *Call, u *url.URL, statusCode int, latency time.Duration, body []byte) {
+	if !c.debugEnabled(ctx) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Method),
+		slog.String("path", u.Path),
+		slog.Int("status", statusCode),
+		slog.Duration("latency", latency),
+		slog.Int("body_size", len(body)),
+	}
+	if len(body) > 0 {
+		attrs = append(attrs, slog.String("body", string(redactJSON(body))))
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "cagc: response", attrs...)
+}
+
+// logFailure logs an attempt that did not produce a response
+func (c *Client) logFailure(ctx context.Context, call *Call, u *url.URL, latency time.Duration, err error) {
+	if !c.debugEnabled(ctx) {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "cagc: request failed",
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Method),
+		slog.String("path", u.Path),
+		slog.Duration("latency", latency),
+		slog.String("error", err.Error()),
+	)
+}
+
+// secretKeys are JSON fields that always hold credentials
+var secretKeys = map[string]bool{
+	"password":                      true,
+	"token":                         true,
+	"private_key":                   true,
+	"sentinel_token":                true,
+	"logdrain_axiom_api_key":        true,
+	"logdrain_newrelic_license_key": true,
+}
+
+// secretSuffixes mark JSON fields holding credentials by naming convention,
+// e.g. postgres_password or mariadb_root_password
+var secretSuffixes = []string{"_password", "_token", "_api_key", "_license_key", "_secret"}
+
+// isSecretKey reports whether a JSON field name holds a credential
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if secretKeys[key] || strings.HasPrefix(key, "manual_webhook_secret") {
+		return true
+	}
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON returns a copy of a JSON document with secret values replaced.
+// Bodies that are not JSON are dropped entirely since they cannot be inspected.
+func redactJSON(data []byte) []byte {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(redacted)
+	}
+	out, err := json.Marshal(redactValue(doc))
+	if err != nil {
+		return []byte(redacted)
+	}
+	return out
+}
+
+// redactValue walks a decoded JSON value and replaces secrets in place
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		// Environment variables are {"key": ..., "value": ...} pairs whose
+		// value and resolved real_value may be anything, so both are always
+		// treated as secrets
+		_, isEnv := v["key"]
+		for key, value := range v {
+			if isSecretKey(key) || (isEnv && (key == "value" || key == "real_value")) {
+				if value != nil && value != "" {
+					v[key] = redacted
+				}
+				continue
+			}
+			if s, ok := value.(string); ok && strings.HasSuffix(strings.ToLower(key), "_url") {
+				v[key] = stripUserinfo(s)
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return v
+}
+
+// stripUserinfo removes credentials such as user:password@ from a URL, e.g.
+// the internal_db_url of a database. Values that do not parse are redacted
+// when they look like they could hold credentials.
+func stripUserinfo(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		if strings.Contains(value, "@") {
+			return redacted
+		}
+		return value
+	}
+	if u.User == nil {
+		return value
+	}
+	u.User = nil
+	return u.String()
+}
+
+// redactedLogValue renders a value as JSON with its secrets redacted
+func redactedLogValue(v interface{}) slog.Value {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return slog.StringValue(redacted)
+	}
+	return slog.StringValue(string(redactJSON(data)))
+}
+
+// LogValue implements slog.LogValuer so that logging a client never exposes its token
+func (c *Client) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("base_url", c.BaseURL.String()),
+		slog.String("token", redacted),
+	)
+}
+
+// LogValue implements slog.LogValuer with the passwords redacted
+func (d Database) LogValue() slog.Value { return redactedLogValue(d) }
+
+// LogValue implements slog.LogValuer with the key material redacted
+func (k PrivateKey) LogValue() slog.Value { return redactedLogValue(k) }
+
+// LogValue implements slog.LogValuer with the value redacted
+func (e EnvironmentVariable) LogValue() slog.Value {
+	if e.Value != "" {
+		e.Value = redacted
+	}
+	return redactedLogValue(e)
+}
+
+// LogValue implements slog.LogValuer with the API keys and tokens redacted
+func (s ServerSetting) LogValue() slog.Value { return redactedLogValue(s) }
+
+// LogValue implements slog.LogValuer with the settings secrets redacted
+func (s Server) LogValue() slog.Value { return redactedLogValue(s) }
+
+// LogValue implements slog.LogValuer with the webhook secrets redacted
+func (a Application) LogValue() slog.Value { return redactedLogValue(a) }
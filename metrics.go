@@ -0,0 +1,84 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// MetricsRecorder receives one measurement per API call, after all retries.
+// Implementations must be safe for concurrent use. See the cagcprom package
+// for a Prometheus implementation.
+type MetricsRecorder interface {
+	RecordCall(ctx context.Context, m CallMetrics)
+}
+
+// CallMetrics describes the outcome of a single API call
+type CallMetrics struct {
+	// Operation is the OpenAPI operationId of the call
+	Operation string
+	// Method is the HTTP method
+	Method string
+	// StatusCode is the status of the last response, or 0 when none arrived
+	StatusCode int
+	// StatusClass is "2xx", "4xx", "5xx" and so on, "error" when no response
+	// arrived, or "none" when middleware answered the call without sending it
+	StatusClass string
+	// Duration is the wall time of the call including retries and waits
+	Duration time.Duration
+	// Retries is the number of attempts beyond the first
+	Retries int
+	// RequestBytes and ResponseBytes count body bytes over all attempts
+	RequestBytes  int64
+	ResponseBytes int64
+	// Err is the error returned to the caller, if any
+	Err error
+}
+
+// WithMetrics reports every API call to recorder
+func WithMetrics(recorder MetricsRecorder) Option {
+	return optionFunc(func(c *Client) error {
+		if recorder == nil {
+			return errors.New("cagc: WithMetrics requires a non-nil recorder")
+		}
+		c.metrics = recorder
+		return nil
+	})
+}
+
+// recordMetrics reports a finished call to the metrics recorder
+func (c *Client) recordMetrics(ctx context.Context, call *Call, start time.Time, err error) {
+	if c.metrics == nil {
+		return
+	}
+
+	retries := 0
+	if call.Attempts > 1 {
+		retries = call.Attempts - 1
+	}
+
+	c.metrics.RecordCall(ctx, CallMetrics{
+		Operation:     call.Operation,
+		Method:        call.Method,
+		StatusCode:    call.StatusCode,
+		StatusClass:   statusClass(call.StatusCode, err),
+		Duration:      time.Since(start),
+		Retries:       retries,
+		RequestBytes:  call.requestBytes,
+		ResponseBytes: call.responseBytes,
+		Err:           err,
+	})
+}
+
+// statusClass groups a status code into its class, e.g. 404 into "4xx"
+func statusClass(statusCode int, err error) string {
+	switch {
+	case statusCode > 0:
+		return strconv.Itoa(statusCode/100) + "xx"
+	case err != nil:
+		return "error"
+	default:
+		return "none"
+	}
+}
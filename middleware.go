@@ -0,0 +1,74 @@
+package cagc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Call describes a single API call as it passes through the middleware chain.
+// Middleware may inspect and modify the request fields before calling the next
+// handler and read the response fields after it returns.
+type Call struct {
+	// Operation is the OpenAPI operationId of the call, e.g. "list-applications"
+	Operation string
+	// Method is the HTTP method
+	Method string
+	// Path is the escaped API path relative to the API prefix, e.g. "/applications/abc"
+	Path string
+	// Query holds the query parameters sent with the request
+	Query url.Values
+	// Header holds extra headers sent with the request
+	Header http.Header
+	// Body is the request payload before JSON encoding, or nil
+	Body interface{}
+
+	// Result is the pointer the response body is decoded into, or nil when
+	// the response body is discarded. A middleware that short-circuits the
+	// call can fill it in itself.
+	Result interface{}
+	// StatusCode is the HTTP status code of the response, set once it arrived
+	StatusCode int
+	// Attempts is the number of attempts made, including retries
+	Attempts int
+
+	noCache        bool
+	stream         func(io.Reader) error
+	streamed       bool
+	bodyTruncated  bool
+	requestBytes   int64
+	responseBytes  int64
+	responseHeader http.Header
+	responseBody   []byte
+}
+
+// Handler executes a Call
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps a Handler with cross-cutting behavior. A middleware can
+// short-circuit the call by returning without invoking next.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware registers middleware around every API call of the client.
+// The first middleware is the outermost one; middleware from repeated options
+// is appended in order.
+func WithMiddleware(middleware ...Middleware) Option {
+	return optionFunc(func(c *Client) error {
+		for _, mw := range middleware {
+			if mw != nil {
+				c.middleware = append(c.middleware, mw)
+			}
+		}
+		return nil
+	})
+}
+
+// chain wraps the final handler with the registered middleware
+func (c *Client) chain(final Handler) Handler {
+	h := final
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
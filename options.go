@@ -0,0 +1,96 @@
+package cagc
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by NewClient
+type Option interface {
+	apply(*Client) error
+}
+
+// optionFunc adapts a plain function to the Option interface
+type optionFunc func(*Client) error
+
+func (f optionFunc) apply(c *Client) error {
+	return f(c)
+}
+
+// WithHTTPClient uses hc for all requests instead of a fresh http.Client.
+// The client is copied, so later options such as WithTimeout never modify hc.
+func WithHTTPClient(hc *http.Client) Option {
+	return optionFunc(func(c *Client) error {
+		if hc == nil {
+			return errors.New("cagc: WithHTTPClient requires a non-nil *http.Client")
+		}
+		clone := *hc
+		c.httpClient = &clone
+		return nil
+	})
+}
+
+// WithTimeout sets the overall timeout of every HTTP request, including
+// connection setup and reading the response body. As a call option it limits
+// a single call, including its retries.
+func WithTimeout(d time.Duration) SharedOption {
+	return sharedOption{
+		client: func(c *Client) error {
+			if d < 0 {
+				return errors.New("cagc: WithTimeout requires a non-negative duration")
+			}
+			c.timeout = d
+			return nil
+		},
+		call: func(o *callOptions) error {
+			if d < 0 {
+				return errors.New("cagc: WithTimeout requires a non-negative duration")
+			}
+			o.timeout = d
+			return nil
+		},
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for all requests, for example
+// to configure proxies or connection pooling
+func WithTransport(rt http.RoundTripper) Option {
+	return optionFunc(func(c *Client) error {
+		if rt == nil {
+			return errors.New("cagc: WithTransport requires a non-nil http.RoundTripper")
+		}
+		c.transport = rt
+		return nil
+	})
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return optionFunc(func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	})
+}
+
+// WithHeader adds a header that is sent with every request, or with a single
+// call when used as a call option. The Authorization header is always set from
+// the client token and cannot be overridden here.
+func WithHeader(key, value string) SharedOption {
+	return sharedOption{
+		client: func(c *Client) error {
+			if key == "" {
+				return errors.New("cagc: WithHeader requires a header name")
+			}
+			c.headers.Add(key, value)
+			return nil
+		},
+		call: func(o *callOptions) error {
+			if key == "" {
+				return errors.New("cagc: WithHeader requires a header name")
+			}
+			o.header.Add(key, value)
+			return nil
+		},
+	}
+}
@@ -2,36 +2,167 @@ package cagc
 
 import (
 	"context"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
 )
 
 // ListPrivateKeys lists all private keys
-func (c *Client) ListPrivateKeys(ctx context.Context) ([]PrivateKey, error) {
+func (c *Client) ListPrivateKeys(ctx context.Context, opts ...CallOption) ([]PrivateKey, error) {
 	var keys []PrivateKey
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/security/keys", nil, &keys)
+	err := c.doRequest(ctx, "list-private-keys", http.MethodGet, apiPath("security", "keys"), nil, &keys, opts...)
 	return keys, err
 }
 
 // GetPrivateKey gets a private key by UUID
-func (c *Client) GetPrivateKey(ctx context.Context, uuid string) (*PrivateKey, error) {
-	path := fmt.Sprintf("/api/v1/security/keys/%s", uuid)
+func (c *Client) GetPrivateKey(ctx context.Context, uuid string, opts ...CallOption) (*PrivateKey, error) {
+	path := apiPath("security", "keys", uuid)
 	var key PrivateKey
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &key)
+	err := c.doRequest(ctx, "get-private-key-by-uuid", http.MethodGet, path, nil, &key, opts...)
 	return &key, err
 }
 
-// CreatePrivateKey creates a new private key
-func (c *Client) CreatePrivateKey(ctx context.Context, key PrivateKey) (*CreateResponse, error) {
+// CreatePrivateKey creates a new private key. The key material is checked
+// with ValidatePrivateKey before it is uploaded.
+func (c *Client) CreatePrivateKey(ctx context.Context, key PrivateKey, opts ...CallOption) (*CreateResponse, error) {
+	if _, err := ValidatePrivateKey(key.PrivateKey); err != nil {
+		return nil, err
+	}
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/security/keys", key, &response)
+	err := c.doRequest(ctx, "create-private-key", http.MethodPost, apiPath("security", "keys"), key, &response, opts...)
 	return &response, err
 }
 
 // DeletePrivateKey deletes a private key
-func (c *Client) DeletePrivateKey(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/security/keys/%s", uuid)
+func (c *Client) DeletePrivateKey(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("security", "keys", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-private-key-by-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
+
+// privateKeyUpdate is the payload of update-private-key. The server looks the
+// key up by the UUID in the body.
+type privateKeyUpdate struct {
+	UUID        string `json:"uuid"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	PrivateKey  string `json:"private_key"`
+}
+
+// UpdatePrivateKey updates the private key with the given UUID. The request
+// is PATCH /security/keys with the UUID in the body; the bundled spec omits
+// uuid from that body schema, but the server needs it to find the key. The
+// key material is checked with ValidatePrivateKey before it is uploaded.
+func (c *Client) UpdatePrivateKey(ctx context.Context, uuid string, key PrivateKey, opts ...CallOption) (*CreateResponse, error) {
+	if strings.TrimSpace(uuid) == "" {
+		return nil, &ValidationError{Errors: map[string][]string{"uuid": {"is required"}}}
+	}
+	if _, err := ValidatePrivateKey(key.PrivateKey); err != nil {
+		return nil, err
+	}
+	update := privateKeyUpdate{
+		UUID:        uuid,
+		Name:        key.Name,
+		Description: key.Description,
+		PrivateKey:  key.PrivateKey,
+	}
+	var response CreateResponse
+	err := c.doRequest(ctx, "update-private-key", http.MethodPatch, apiPath("security", "keys"), update, &response, opts...)
+	return &response, err
+}
+
+// minRSABits is the smallest RSA key accepted by ValidatePrivateKey
+const minRSABits = 2048
+
+// KeyInfo describes private key material checked by ValidatePrivateKey
+type KeyInfo struct {
+	// Algorithm is the SSH key type, e.g. "ssh-ed25519" or "ssh-rsa"
+	Algorithm string
+	// Bits is the key size
+	Bits int
+	// Fingerprint is the SHA-256 fingerprint of the public key as printed by
+	// "ssh-keygen -l", e.g. "SHA256:..."
+	Fingerprint string
+	// PublicKey is the public key in authorized_keys format
+	PublicKey string
+}
+
+// ValidatePrivateKey checks key material locally before it is uploaded: it
+// must be a PEM encoded RSA (at least 2048 bits), ECDSA or Ed25519 private
+// key without a passphrase, in OpenSSH, PKCS#1, PKCS#8 or SEC 1 format.
+// Failures are returned as a *ValidationError for the private_key field,
+// which matches ErrValidation.
+func ValidatePrivateKey(material string) (*KeyInfo, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return &ValidationError{Errors: map[string][]string{"private_key": {fmt.Sprintf(format, args...)}}}
+	}
+
+	material = strings.TrimSpace(material)
+	if material == "" {
+		return nil, invalid("is required")
+	}
+	block, _ := pem.Decode([]byte(material))
+	if block == nil {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(material)); err == nil {
+			return nil, invalid("is a public key, not a private key")
+		}
+		return nil, invalid("is not PEM encoded")
+	}
+	if strings.Contains(block.Type, "PUBLIC KEY") {
+		return nil, invalid("is a public key, not a private key")
+	}
+
+	raw, err := ssh.ParseRawPrivateKey([]byte(material))
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) || pemIsEncrypted(block) {
+			return nil, invalid("is protected by a passphrase; Coolify needs an unencrypted key")
+		}
+		return nil, invalid("cannot be parsed: %v", err)
+	}
+
+	var bits int
+	switch key := raw.(type) {
+	case *rsa.PrivateKey:
+		bits = key.N.BitLen()
+		if bits < minRSABits {
+			return nil, invalid("is a %d-bit RSA key; at least %d bits are required", bits, minRSABits)
+		}
+	case *ecdsa.PrivateKey:
+		bits = key.Curve.Params().BitSize
+	case ed25519.PrivateKey, *ed25519.PrivateKey:
+		bits = 256
+	case *dsa.PrivateKey:
+		return nil, invalid("uses the unsupported DSA algorithm; use Ed25519, ECDSA or RSA")
+	default:
+		return nil, invalid("uses an unsupported algorithm %T", raw)
+	}
+
+	signer, err := ssh.NewSignerFromKey(raw)
+	if err != nil {
+		return nil, invalid("cannot be used for SSH: %v", err)
+	}
+	pub := signer.PublicKey()
+	return &KeyInfo{
+		Algorithm:   pub.Type(),
+		Bits:        bits,
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+	}, nil
+}
+
+// pemIsEncrypted reports whether a PEM block uses legacy RFC 1423 or PKCS#8
+// encryption
+func pemIsEncrypted(block *pem.Block) bool {
+	_, legacy := block.Headers["DEK-Info"]
+	return legacy || block.Type == "ENCRYPTED PRIVATE KEY"
+}
@@ -0,0 +1,113 @@
+package cagc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ed25519PEM returns a fresh unencrypted OpenSSH Ed25519 private key
+func ed25519PEM(t *testing.T) string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(block))
+}
+
+func TestUpdatePrivateKeySendsUUID(t *testing.T) {
+	var body map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/api/v1/security/keys" {
+			t.Errorf("got %s %s, want PATCH /api/v1/security/keys", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"uuid":"key"}`))
+	})
+
+	resp, err := c.UpdatePrivateKey(context.Background(), "key", PrivateKey{
+		Name:        "deploy",
+		Description: "CI deploy key",
+		PrivateKey:  ed25519PEM(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.UUID != "key" {
+		t.Errorf("got UUID %q, want key", resp.UUID)
+	}
+	if body["uuid"] != "key" {
+		t.Errorf("body uuid = %v, want key", body["uuid"])
+	}
+	if body["name"] != "deploy" || body["private_key"] == nil {
+		t.Errorf("got body %v", body)
+	}
+}
+
+func TestUpdatePrivateKeyRequiresUUID(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request sent without a UUID: %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := c.UpdatePrivateKey(context.Background(), "", PrivateKey{PrivateKey: ed25519PEM(t)})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Errors["uuid"] == nil {
+		t.Errorf("got %v, want a validation error for uuid", err)
+	}
+}
+
+func TestValidatePrivateKey(t *testing.T) {
+	weakRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weakPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(weakRSA)}))
+
+	tests := []struct {
+		name     string
+		material string
+		wantErr  bool
+	}{
+		{"ed25519", ed25519PEM(t), false},
+		{"empty", "", true},
+		{"not PEM", "ssh-ed25519 AAAA", true},
+		{"weak RSA", weakPEM, true},
+		{"public key", "-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ValidatePrivateKey(tt.material)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("got %v, want a validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Algorithm != ssh.KeyAlgoED25519 || !strings.HasPrefix(info.Fingerprint, "SHA256:") {
+				t.Errorf("got %+v", info)
+			}
+		})
+	}
+}
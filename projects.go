@@ -7,39 +7,105 @@ import (
 )
 
 // ListProjects lists all projects
-func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+func (c *Client) ListProjects(ctx context.Context, opts ...CallOption) ([]Project, error) {
 	var projects []Project
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/projects", nil, &projects)
+	err := c.doRequest(ctx, "list-projects", http.MethodGet, apiPath("projects"), nil, &projects, opts...)
 	return projects, err
 }
 
 // GetProject gets a project by UUID
-func (c *Client) GetProject(ctx context.Context, uuid string) (*Project, error) {
-	path := fmt.Sprintf("/api/v1/projects/%s", uuid)
+func (c *Client) GetProject(ctx context.Context, uuid string, opts ...CallOption) (*Project, error) {
+	path := apiPath("projects", uuid)
 	var project Project
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &project)
+	err := c.doRequest(ctx, "get-project-by-uuid", http.MethodGet, path, nil, &project, opts...)
 	return &project, err
 }
 
 // CreateProject creates a new project
-func (c *Client) CreateProject(ctx context.Context, project Project) (*CreateResponse, error) {
+func (c *Client) CreateProject(ctx context.Context, project Project, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/projects", project, &response)
+	err := c.doRequest(ctx, "create-project", http.MethodPost, apiPath("projects"), project, &response, opts...)
 	return &response, err
 }
 
 // UpdateProject updates an existing project
-func (c *Client) UpdateProject(ctx context.Context, uuid string, project Project) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/projects/%s", uuid)
+func (c *Client) UpdateProject(ctx context.Context, uuid string, project Project, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("projects", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, project, &response)
+	err := c.doRequest(ctx, "update-project-by-uuid", http.MethodPatch, path, project, &response, opts...)
 	return &response, err
 }
 
 // DeleteProject deletes a project
-func (c *Client) DeleteProject(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/projects/%s", uuid)
+func (c *Client) DeleteProject(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("projects", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-project-by-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
+
+// GetEnvironment gets an environment of a project by name or UUID, including
+// its applications, databases and services
+func (c *Client) GetEnvironment(ctx context.Context, projectUUID, nameOrUUID string, opts ...CallOption) (*Environment, error) {
+	path := apiPath("projects", projectUUID, nameOrUUID)
+	var environment Environment
+	err := c.doRequest(ctx, "get-environment-by-name-or-uuid", http.MethodGet, path, nil, &environment, opts...)
+	return &environment, err
+}
+
+// ResolveProject finds a project by UUID or by its unique name. A missing
+// project matches ErrNotFound.
+func (c *Client) ResolveProject(ctx context.Context, nameOrUUID string, opts ...CallOption) (*Project, error) {
+	projects, err := c.ListProjects(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Project
+	for _, project := range projects {
+		if project.UUID == nameOrUUID {
+			return &project, nil
+		}
+		if project.Name == nameOrUUID {
+			matches = append(matches, project)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: project %q", ErrNotFound, nameOrUUID)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("cagc: %d projects are named %q, use the project UUID", len(matches), nameOrUUID)
+	}
+}
+
+// ResolveEnvironment turns a project and environment, each given by name or
+// UUID, into the UUIDs used by create requests. A missing project or
+// environment matches ErrNotFound.
+func (c *Client) ResolveEnvironment(ctx context.Context, project, environment string, opts ...CallOption) (*EnvironmentRef, error) {
+	p, err := c.ResolveProject(ctx, project, opts...)
+	if err != nil {
+		return nil, err
+	}
+	env, err := c.GetEnvironment(ctx, p.UUID, environment, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &EnvironmentRef{
+		ProjectUUID:     p.UUID,
+		EnvironmentUUID: env.UUID,
+		EnvironmentName: env.Name,
+	}, nil
+}
+
+// DatabaseFields returns the common database create fields for the
+// environment on the given server
+func (r EnvironmentRef) DatabaseFields(serverUUID string) DatabaseCreateFields {
+	return DatabaseCreateFields{
+		ServerUUID:      serverUUID,
+		ProjectUUID:     r.ProjectUUID,
+		EnvironmentName: r.EnvironmentName,
+		EnvironmentUUID: r.EnvironmentUUID,
+	}
+}
@@ -0,0 +1,158 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to requestsPerSecond requests on average,
+// allowing bursts of up to burst requests. When the server answers with 429
+// the rate is halved (and paused for any Retry-After period), then recovers
+// gradually with every successful response.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return optionFunc(func(c *Client) error {
+		if requestsPerSecond <= 0 {
+			return errors.New("cagc: WithRateLimit requires a positive rate")
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = newRateLimiter(requestsPerSecond, burst)
+		return nil
+	})
+}
+
+// WithMaxInFlight limits the number of requests the client has in flight at
+// the same time. Further requests block until a slot frees up or their
+// context ends.
+func WithMaxInFlight(n int) Option {
+	return optionFunc(func(c *Client) error {
+		if n < 1 {
+			return errors.New("cagc: WithMaxInFlight requires at least one slot")
+		}
+		c.inFlight = make(chan struct{}, n)
+		return nil
+	})
+}
+
+// rateLimiter is a token bucket whose refill rate adapts to 429 responses
+type rateLimiter struct {
+	mu sync.Mutex
+
+	maxRate float64
+	minRate float64
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	paused  time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		maxRate: requestsPerSecond,
+		minRate: requestsPerSecond / 16,
+		rate:    requestsPerSecond,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx ends
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and otherwise returns how long to
+// wait before trying again
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.paused) {
+		return l.paused.Sub(now)
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// observe adapts the rate to the outcome of a request
+func (l *rateLimiter) observe(statusCode int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if statusCode == http.StatusTooManyRequests {
+		l.rate /= 2
+		if l.rate < l.minRate {
+			l.rate = l.minRate
+		}
+		l.tokens = 0
+		if d, ok := parseRetryAfter(header, time.Now()); ok {
+			if until := time.Now().Add(d); until.After(l.paused) {
+				l.paused = until
+			}
+		}
+		return
+	}
+
+	if statusCode < 500 && l.rate < l.maxRate {
+		l.rate += l.maxRate / 20
+		if l.rate > l.maxRate {
+			l.rate = l.maxRate
+		}
+	}
+}
+
+// acquire waits for the rate limiter and a free in-flight slot. The returned
+// function releases the slot and must be called once the response is read.
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.inFlight != nil {
+		select {
+		case c.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if c.inFlight != nil {
+			<-c.inFlight
+		}
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
@@ -0,0 +1,158 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	start := time.Now()
+	l := newRateLimiter(10, 2)
+	l.last = start
+
+	tests := []struct {
+		name string
+		at   time.Duration
+		want time.Duration
+	}{
+		{"first burst token", 0, 0},
+		{"second burst token", 0, 0},
+		{"bucket empty", 0, 100 * time.Millisecond},
+		{"half refilled", 50 * time.Millisecond, 50 * time.Millisecond},
+		{"refilled", 100 * time.Millisecond, 0},
+	}
+	for _, tt := range tests {
+		if got := l.reserve(start.Add(tt.at)); (got - tt.want).Abs() > time.Microsecond {
+			t.Errorf("%s: reserve = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterSlowsDownOn429(t *testing.T) {
+	l := newRateLimiter(16, 1)
+
+	l.observe(http.StatusTooManyRequests, nil)
+	if l.rate != 8 {
+		t.Errorf("rate after 429 = %v, want 8", l.rate)
+	}
+	if l.tokens != 0 {
+		t.Errorf("tokens after 429 = %v, want 0", l.tokens)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.observe(http.StatusTooManyRequests, nil)
+	}
+	if l.rate != 1 {
+		t.Errorf("rate after many 429s = %v, want the floor of 1", l.rate)
+	}
+
+	l.observe(http.StatusOK, nil)
+	if math.Abs(l.rate-1.8) > 1e-9 {
+		t.Errorf("rate after a success = %v, want 1.8", l.rate)
+	}
+	for i := 0; i < 100; i++ {
+		l.observe(http.StatusOK, nil)
+	}
+	if l.rate != 16 {
+		t.Errorf("rate after recovering = %v, want 16", l.rate)
+	}
+
+	l.rate = 8
+	l.observe(http.StatusServiceUnavailable, nil)
+	if l.rate != 8 {
+		t.Errorf("rate after a 503 = %v, want it unchanged", l.rate)
+	}
+}
+
+func TestRateLimiterRetryAfterPauses(t *testing.T) {
+	l := newRateLimiter(100, 10)
+	l.observe(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"2"}})
+
+	delay := l.reserve(time.Now())
+	if delay < time.Second || delay > 2*time.Second {
+		t.Errorf("reserve during Retry-After pause = %v, want about 2s", delay)
+	}
+}
+
+func TestRateLimiterWaitHonorsContext(t *testing.T) {
+	l := newRateLimiter(0.1, 1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var current, peak int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		w.Write([]byte(`[]`))
+	}, WithMaxInFlight(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.ListApplications(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got != 2 {
+		t.Errorf("peak in-flight requests = %d, want 2", got)
+	}
+}
+
+func TestRateLimitedClient(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}, WithRateLimit(50, 1))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := c.ListApplications(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("4 requests at 50/s with burst 1 took %v, want at least 50ms", elapsed)
+	}
+}
+
+func TestRateLimitOptionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"zero rate", WithRateLimit(0, 1)},
+		{"negative rate", WithRateLimit(-1, 1)},
+		{"no in-flight slots", WithMaxInFlight(0)},
+	}
+	for _, tt := range tests {
+		if _, err := NewClient("https://coolify.example.com", "token", tt.opt); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
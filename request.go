@@ -0,0 +1,125 @@
+package cagc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// customOperation names calls made with Do that do not set an Operation
+const customOperation = "custom"
+
+// Request describes a raw API request sent with Do, e.g. for an endpoint that
+// has no wrapper yet
+type Request struct {
+	// Operation names the call in traces, logs and metrics. It defaults to
+	// "custom"; use the OpenAPI operationId when there is one.
+	Operation string
+	// Method is the HTTP method, GET when empty
+	Method string
+	// Path is the escaped path relative to the API prefix, e.g.
+	// "/applications/abc/logs". A query string in the path is merged into Query.
+	Path string
+	// Query holds the query parameters
+	Query url.Values
+	// Header holds extra headers sent with the request
+	Header http.Header
+	// Body is encoded as JSON, or omitted when nil. Use json.RawMessage to
+	// send a pre-encoded body.
+	Body interface{}
+}
+
+// Response is the raw response to a Request
+type Response struct {
+	// StatusCode is the HTTP status code
+	StatusCode int
+	// Header holds the response headers; it is empty for responses served
+	// from the cache or synthesized in dry-run mode
+	Header http.Header
+	// Body is the raw response body
+	Body []byte
+	// Attempts is the number of attempts made, including retries
+	Attempts int
+}
+
+// Do sends a raw API request with the same authentication, retries, rate
+// limiting, middleware and observability as the typed methods. Non-2xx
+// responses are returned as an *APIError.
+func (c *Client) Do(ctx context.Context, req Request, opts ...CallOption) (*Response, error) {
+	call, err := c.do(ctx, req, nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	header := call.responseHeader
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &Response{
+		StatusCode: call.StatusCode,
+		Header:     header,
+		Body:       call.responseBody,
+		Attempts:   call.Attempts,
+	}, nil
+}
+
+// DoJSON sends a raw API request like Client.Do and decodes the JSON response
+// body into a T
+func DoJSON[T any](ctx context.Context, c *Client, req Request, opts ...CallOption) (T, error) {
+	var out T
+	_, err := c.do(ctx, req, &out, opts...)
+	return out, err
+}
+
+// do performs a request with its call options through the middleware chain
+// and decodes the response body into v unless v is nil
+func (c *Client) do(ctx context.Context, req Request, v interface{}, opts ...CallOption) (*Call, error) {
+	rawPath, rawQuery, _ := strings.Cut(req.Path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range req.Query {
+		query[key] = append(query[key], values...)
+	}
+
+	if !strings.HasPrefix(rawPath, "/") {
+		rawPath = "/" + rawPath
+	}
+	if c.apiPrefix != "" && strings.HasPrefix(rawPath, c.apiPrefix+"/") {
+		rawPath = strings.TrimPrefix(rawPath, c.apiPrefix)
+	}
+
+	call := &Call{
+		Operation: req.Operation,
+		Method:    req.Method,
+		Path:      rawPath,
+		Query:     query,
+		Header:    make(http.Header),
+		Body:      req.Body,
+		Result:    v,
+	}
+	if call.Operation == "" {
+		call.Operation = customOperation
+	}
+	if call.Method == "" {
+		call.Method = http.MethodGet
+	}
+	for key, values := range req.Header {
+		call.Header[key] = append([]string(nil), values...)
+	}
+
+	ctx, cancel, err := applyCallOptions(ctx, call, opts)
+	defer cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, call)
+	err = c.chain(c.execute)(ctx, call)
+	endSpan(span, call, err)
+	c.recordMetrics(ctx, call, start, err)
+	return call, err
+}
@@ -2,51 +2,50 @@ package cagc
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
 // ListResources lists all resources
-func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+func (c *Client) ListResources(ctx context.Context, opts ...CallOption) ([]Resource, error) {
 	var resources []Resource
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/resources", nil, &resources)
+	err := c.doRequest(ctx, "list-resources", http.MethodGet, apiPath("resources"), nil, &resources, opts...)
 	return resources, err
 }
 
 // ListDestinations lists all destinations (keeping this for backward compatibility)
-func (c *Client) ListDestinations(ctx context.Context) ([]Destination, error) {
+func (c *Client) ListDestinations(ctx context.Context, opts ...CallOption) ([]Destination, error) {
 	var destinations []Destination
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/destinations", nil, &destinations)
+	err := c.doRequest(ctx, "list-destinations", http.MethodGet, apiPath("destinations"), nil, &destinations, opts...)
 	return destinations, err
 }
 
 // GetDestination gets a destination by UUID (keeping this for backward compatibility)
-func (c *Client) GetDestination(ctx context.Context, uuid string) (*Destination, error) {
-	path := fmt.Sprintf("/api/v1/destinations/%s", uuid)
+func (c *Client) GetDestination(ctx context.Context, uuid string, opts ...CallOption) (*Destination, error) {
+	path := apiPath("destinations", uuid)
 	var destination Destination
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &destination)
+	err := c.doRequest(ctx, "get-destination-by-uuid", http.MethodGet, path, nil, &destination, opts...)
 	return &destination, err
 }
 
 // CreateDestination creates a new destination (keeping this for backward compatibility)
-func (c *Client) CreateDestination(ctx context.Context, destination Destination) (*CreateResponse, error) {
+func (c *Client) CreateDestination(ctx context.Context, destination Destination, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/destinations", destination, &response)
+	err := c.doRequest(ctx, "create-destination", http.MethodPost, apiPath("destinations"), destination, &response, opts...)
 	return &response, err
 }
 
 // UpdateDestination updates an existing destination (keeping this for backward compatibility)
-func (c *Client) UpdateDestination(ctx context.Context, uuid string, destination Destination) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/destinations/%s", uuid)
+func (c *Client) UpdateDestination(ctx context.Context, uuid string, destination Destination, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("destinations", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, destination, &response)
+	err := c.doRequest(ctx, "update-destination-by-uuid", http.MethodPatch, path, destination, &response, opts...)
 	return &response, err
 }
 
 // DeleteDestination deletes a destination (keeping this for backward compatibility)
-func (c *Client) DeleteDestination(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/destinations/%s", uuid)
+func (c *Client) DeleteDestination(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("destinations", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-destination-by-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
@@ -0,0 +1,197 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryStatusCodes are the response codes retried when a RetryPolicy
+// does not list its own
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryPolicy is a reasonable policy for riding out Coolify proxy
+// restarts: up to four attempts with backoff between 250ms and 5s
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+}
+
+// RetryPolicy controls how requests are retried after transient failures.
+// GET requests that only read are retried when the policy allows more than
+// one attempt. GET requests that trigger an action, such as start, stop,
+// restart, deploy and enable-api, as well as DELETE and PATCH are only
+// retried when explicitly enabled. POST requests are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry. It doubles
+	// with every further attempt and is jittered.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed backoff. A Retry-After header sent by the
+	// server takes precedence over the computed backoff.
+	MaxBackoff time.Duration
+	// RetryActions enables retries for GET requests that trigger an action.
+	// A proxy may answer 502 or 503 after Coolify accepted the request, so a
+	// retry can restart or deploy a resource twice.
+	RetryActions bool
+	// RetryDelete enables retries for DELETE requests
+	RetryDelete bool
+	// RetryPatch enables retries for PATCH requests
+	RetryPatch bool
+	// RetryStatusCodes lists the response codes that trigger a retry.
+	// Nil means DefaultRetryStatusCodes.
+	RetryStatusCodes []int
+}
+
+// WithRetryPolicy sets the retry policy used for every request of the
+// client, or for a single call when used as a call option
+func WithRetryPolicy(policy RetryPolicy) SharedOption {
+	validate := func() error {
+		if policy.MaxAttempts < 0 || policy.InitialBackoff < 0 || policy.MaxBackoff < 0 {
+			return errors.New("cagc: WithRetryPolicy requires non-negative values")
+		}
+		return nil
+	}
+	return sharedOption{
+		client: func(c *Client) error {
+			if err := validate(); err != nil {
+				return err
+			}
+			c.retryPolicy = policy
+			return nil
+		},
+		call: func(o *callOptions) error {
+			if err := validate(); err != nil {
+				return err
+			}
+			o.retryPolicy = &policy
+			return nil
+		},
+	}
+}
+
+type retryPolicyKey struct{}
+
+// ContextWithRetryPolicy returns a context that makes requests using it follow
+// policy instead of the client's retry policy
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// retryPolicyFor returns the retry policy that applies to a request made with ctx
+func (c *Client) retryPolicyFor(ctx context.Context) RetryPolicy {
+	if policy, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		return policy
+	}
+	return c.retryPolicy
+}
+
+// allows reports whether a call may be retried
+func (p RetryPolicy) allows(call *Call) bool {
+	if p.MaxAttempts < 2 {
+		return false
+	}
+	switch call.Method {
+	case http.MethodGet, http.MethodHead:
+		return !isMutation(call) || p.RetryActions
+	case http.MethodDelete:
+		return p.RetryDelete
+	case http.MethodPatch:
+		return p.RetryPatch
+	}
+	return false
+}
+
+// retriesStatus reports whether a response with the given status code is retried
+func (p RetryPolicy) retriesStatus(statusCode int) bool {
+	codes := p.RetryStatusCodes
+	if codes == nil {
+		codes = DefaultRetryStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (1 for the first retry),
+// preferring the Retry-After header of the failed response when present
+func (p RetryPolicy) backoff(retry int, header http.Header) time.Duration {
+	if d, ok := parseRetryAfter(header, time.Now()); ok {
+		return d
+	}
+
+	delay := p.InitialBackoff
+	if delay <= 0 {
+		delay = DefaultRetryPolicy.InitialBackoff
+	}
+	maxDelay := p.MaxBackoff
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryPolicy.MaxBackoff
+	}
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	// Equal jitter: keep half of the delay and randomize the other half so
+	// that clients failing together do not retry in lockstep
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an
+// HTTP date
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleepForRetry waits for delay unless ctx ends first or its deadline would
+// pass before the retry could be sent. It reports whether the retry should go ahead.
+func sleepForRetry(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
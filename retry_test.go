@@ -0,0 +1,280 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient starts an httptest server with handler and returns a client
+// pointed at it
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	c, err := NewClient(srv.URL, "token", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// fastRetries retries up to three attempts without noticeable delays
+var fastRetries = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestRetryMethods(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		call   func(context.Context, *Client) error
+		want   int32
+	}{
+		{
+			name:   "read GET is retried",
+			policy: fastRetries,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetDatabase(ctx, "db")
+				return err
+			},
+			want: 3,
+		},
+		{
+			name:   "restart GET is not retried",
+			policy: fastRetries,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.RestartDatabase(ctx, "db")
+				return err
+			},
+			want: 1,
+		},
+		{
+			name:   "deploy GET is not retried",
+			policy: fastRetries,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.DeployByTagOrUUID(ctx, "", "app", false)
+				return err
+			},
+			want: 1,
+		},
+		{
+			name:   "restart GET is retried with RetryActions",
+			policy: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryActions: true},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.RestartDatabase(ctx, "db")
+				return err
+			},
+			want: 3,
+		},
+		{
+			name:   "DELETE is not retried",
+			policy: fastRetries,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.DeleteDatabase(ctx, "db", false, false, false, false)
+				return err
+			},
+			want: 1,
+		},
+		{
+			name:   "DELETE is retried with RetryDelete",
+			policy: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, RetryDelete: true},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.DeleteDatabase(ctx, "db", false, false, false, false)
+				return err
+			},
+			want: 3,
+		},
+		{
+			name:   "POST with an idempotency key is not retried",
+			policy: fastRetries,
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.CreateProject(ctx, Project{Name: "p"}, WithIdempotencyKey("key"))
+				return err
+			},
+			want: 1,
+		},
+		{
+			name:   "retries disabled",
+			policy: RetryPolicy{},
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetDatabase(ctx, "db")
+				return err
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}, WithRetryPolicy(tt.policy))
+
+			err := tt.call(context.Background(), c)
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("got error %v, want a 503 *APIError", err)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.want {
+				t.Errorf("server saw %d requests, want %d", got, tt.want)
+			}
+			if apiErr.Attempts != int(tt.want) {
+				t.Errorf("APIError.Attempts = %d, want %d", apiErr.Attempts, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryStatusCodes(t *testing.T) {
+	tests := []struct {
+		status int
+		want   int32
+	}{
+		{http.StatusTooManyRequests, 3},
+		{http.StatusBadGateway, 3},
+		{http.StatusGatewayTimeout, 3},
+		{http.StatusInternalServerError, 1},
+		{http.StatusNotFound, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var requests int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tt.status)
+			}, WithRetryPolicy(fastRetries))
+
+			if _, err := c.ListApplications(context.Background()); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.want {
+				t.Errorf("server saw %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailure(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"uuid":"app"}]`))
+	}, WithRetryPolicy(fastRetries))
+
+	apps, err := c.ListApplications(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].UUID != "app" {
+		t.Errorf("got %+v", apps)
+	}
+}
+
+func TestRetryPolicyPerCall(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	c.ListApplications(context.Background(), WithRetryPolicy(fastRetries))
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("call option: server saw %d requests, want 3", got)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	c.ListApplications(ContextWithRetryPolicy(context.Background(), fastRetries))
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("context: server saw %d requests, want 3", got)
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	var requests int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetryPolicy(fastRetries))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.ListApplications(ctx); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("gave up after %v, want no wait for a retry past the deadline", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-30 * time.Second).Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			got, ok := parseRetryAfter(header, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := policy.backoff(tt.retry, nil)
+			if got < tt.max/2 || got > tt.max {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.retry, got, tt.max/2, tt.max)
+			}
+		}
+	}
+
+	header := http.Header{"Retry-After": []string{"3"}}
+	if got := policy.backoff(1, header); got != 3*time.Second {
+		t.Errorf("backoff with Retry-After = %v, want 3s", got)
+	}
+}
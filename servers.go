@@ -2,68 +2,67 @@ package cagc
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
 // ListServers lists all servers
-func (c *Client) ListServers(ctx context.Context) ([]Server, error) {
+func (c *Client) ListServers(ctx context.Context, opts ...CallOption) ([]Server, error) {
 	var servers []Server
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/servers", nil, &servers)
+	err := c.doRequest(ctx, "list-servers", http.MethodGet, apiPath("servers"), nil, &servers, opts...)
 	return servers, err
 }
 
 // GetServer gets a server by UUID
-func (c *Client) GetServer(ctx context.Context, uuid string) (*Server, error) {
-	path := fmt.Sprintf("/api/v1/servers/%s", uuid)
+func (c *Client) GetServer(ctx context.Context, uuid string, opts ...CallOption) (*Server, error) {
+	path := apiPath("servers", uuid)
 	var server Server
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &server)
+	err := c.doRequest(ctx, "get-server-by-uuid", http.MethodGet, path, nil, &server, opts...)
 	return &server, err
 }
 
 // CreateServer creates a new server
-func (c *Client) CreateServer(ctx context.Context, server Server) (*CreateResponse, error) {
+func (c *Client) CreateServer(ctx context.Context, server Server, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/servers", server, &response)
+	err := c.doRequest(ctx, "create-server", http.MethodPost, apiPath("servers"), server, &response, opts...)
 	return &response, err
 }
 
 // UpdateServer updates an existing server
-func (c *Client) UpdateServer(ctx context.Context, uuid string, server Server) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/servers/%s", uuid)
+func (c *Client) UpdateServer(ctx context.Context, uuid string, server Server, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("servers", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, server, &response)
+	err := c.doRequest(ctx, "update-server-by-uuid", http.MethodPatch, path, server, &response, opts...)
 	return &response, err
 }
 
 // DeleteServer deletes a server
-func (c *Client) DeleteServer(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/servers/%s", uuid)
+func (c *Client) DeleteServer(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("servers", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-server-by-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
 
 // ValidateServer validates a server by UUID
-func (c *Client) ValidateServer(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/servers/%s/validate", uuid)
+func (c *Client) ValidateServer(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("servers", uuid, "validate")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "validate-server-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // GetServerResources gets resources by server UUID
-func (c *Client) GetServerResources(ctx context.Context, uuid string) ([]Resource, error) {
-	path := fmt.Sprintf("/api/v1/servers/%s/resources", uuid)
+func (c *Client) GetServerResources(ctx context.Context, uuid string, opts ...CallOption) ([]Resource, error) {
+	path := apiPath("servers", uuid, "resources")
 	var resources []Resource
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &resources)
+	err := c.doRequest(ctx, "get-resources-by-server-uuid", http.MethodGet, path, nil, &resources, opts...)
 	return resources, err
 }
 
 // GetServerDomains gets domains by server UUID
-func (c *Client) GetServerDomains(ctx context.Context, uuid string) ([]ServerDomain, error) {
-	path := fmt.Sprintf("/api/v1/servers/%s/domains", uuid)
+func (c *Client) GetServerDomains(ctx context.Context, uuid string, opts ...CallOption) ([]ServerDomain, error) {
+	path := apiPath("servers", uuid, "domains")
 	var domains []ServerDomain
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &domains)
+	err := c.doRequest(ctx, "get-domains-by-server-uuid", http.MethodGet, path, nil, &domains, opts...)
 	return domains, err
 }
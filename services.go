@@ -8,38 +8,38 @@ import (
 )
 
 // ListServices lists all services
-func (c *Client) ListServices(ctx context.Context) ([]Service, error) {
+func (c *Client) ListServices(ctx context.Context, opts ...CallOption) ([]Service, error) {
 	var services []Service
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/services", nil, &services)
+	err := c.doRequest(ctx, "list-services", http.MethodGet, apiPath("services"), nil, &services, opts...)
 	return services, err
 }
 
 // GetService gets a service by UUID
-func (c *Client) GetService(ctx context.Context, uuid string) (*Service, error) {
-	path := fmt.Sprintf("/api/v1/services/%s", uuid)
+func (c *Client) GetService(ctx context.Context, uuid string, opts ...CallOption) (*Service, error) {
+	path := apiPath("services", uuid)
 	var service Service
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &service)
+	err := c.doRequest(ctx, "get-service-by-uuid", http.MethodGet, path, nil, &service, opts...)
 	return &service, err
 }
 
 // CreateService creates a new one-click service
-func (c *Client) CreateService(ctx context.Context, service Service) (*CreateResponse, error) {
+func (c *Client) CreateService(ctx context.Context, service Service, opts ...CallOption) (*CreateResponse, error) {
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, "/api/v1/services", service, &response)
+	err := c.doRequest(ctx, "create-service", http.MethodPost, apiPath("services"), service, &response, opts...)
 	return &response, err
 }
 
 // UpdateService updates an existing service
-func (c *Client) UpdateService(ctx context.Context, uuid string, service Service) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s", uuid)
+func (c *Client) UpdateService(ctx context.Context, uuid string, service Service, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", uuid)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, service, &response)
+	err := c.doRequest(ctx, "update-service-by-uuid", http.MethodPatch, path, service, &response, opts...)
 	return &response, err
 }
 
 // DeleteService deletes a service
-func (c *Client) DeleteService(ctx context.Context, uuid string, deleteConfigurations, deleteVolumes, dockerCleanup, deleteConnectedNetworks bool) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s", uuid)
+func (c *Client) DeleteService(ctx context.Context, uuid string, deleteConfigurations, deleteVolumes, dockerCleanup, deleteConnectedNetworks bool, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", uuid)
 	query := url.Values{}
 	query.Add("delete_configurations", fmt.Sprintf("%t", deleteConfigurations))
 	query.Add("delete_volumes", fmt.Sprintf("%t", deleteVolumes))
@@ -47,75 +47,75 @@ func (c *Client) DeleteService(ctx context.Context, uuid string, deleteConfigura
 	query.Add("delete_connected_networks", fmt.Sprintf("%t", deleteConnectedNetworks))
 
 	if len(query) > 0 {
-		path = fmt.Sprintf("%s?%s", path, query.Encode())
+		path += "?" + query.Encode()
 	}
 
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-service-by-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
 
 // StartService starts a service
-func (c *Client) StartService(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/start", uuid)
+func (c *Client) StartService(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", uuid, "start")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "start-service-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // StopService stops a service
-func (c *Client) StopService(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/stop", uuid)
+func (c *Client) StopService(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", uuid, "stop")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "stop-service-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // RestartService restarts a service
-func (c *Client) RestartService(ctx context.Context, uuid string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/restart", uuid)
+func (c *Client) RestartService(ctx context.Context, uuid string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", uuid, "restart")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &response)
+	err := c.doRequest(ctx, "restart-service-by-uuid", http.MethodGet, path, nil, &response, opts...)
 	return &response, err
 }
 
 // ExecuteServiceCommand executes a command on a service's container
-func (c *Client) ExecuteServiceCommand(ctx context.Context, uuid string, command string) (*CommandResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/execute", uuid)
+func (c *Client) ExecuteServiceCommand(ctx context.Context, uuid string, command string, opts ...CallOption) (*CommandResponse, error) {
+	path := apiPath("services", uuid, "execute")
 	req := map[string]string{"command": command}
 	var response CommandResponse
-	err := c.doRequest(ctx, http.MethodPost, path, req, &response)
+	err := c.doRequest(ctx, "execute-command-service", http.MethodPost, path, req, &response, opts...)
 	return &response, err
 }
 
 // ListServiceEnvs lists all environment variables for a service
-func (c *Client) ListServiceEnvs(ctx context.Context, uuid string) ([]EnvironmentVariable, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/envs", uuid)
+func (c *Client) ListServiceEnvs(ctx context.Context, uuid string, opts ...CallOption) ([]EnvironmentVariable, error) {
+	path := apiPath("services", uuid, "envs")
 	var envs []EnvironmentVariable
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &envs)
+	err := c.doRequest(ctx, "list-envs-by-service-uuid", http.MethodGet, path, nil, &envs, opts...)
 	return envs, err
 }
 
 // CreateServiceEnv creates a new environment variable for a service
-func (c *Client) CreateServiceEnv(ctx context.Context, serviceUUID string, env EnvironmentVariable) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/envs", serviceUUID)
+func (c *Client) CreateServiceEnv(ctx context.Context, serviceUUID string, env EnvironmentVariable, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", serviceUUID, "envs")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPost, path, env, &response)
+	err := c.doRequest(ctx, "create-env-by-service-uuid", http.MethodPost, path, env, &response, opts...)
 	return &response, err
 }
 
 // UpdateServiceEnv updates an environment variable for a service
-func (c *Client) UpdateServiceEnv(ctx context.Context, serviceUUID string, env EnvironmentVariable) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/envs", serviceUUID)
+func (c *Client) UpdateServiceEnv(ctx context.Context, serviceUUID string, env EnvironmentVariable, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", serviceUUID, "envs")
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodPatch, path, env, &response)
+	err := c.doRequest(ctx, "update-env-by-service-uuid", http.MethodPatch, path, env, &response, opts...)
 	return &response, err
 }
 
 // DeleteServiceEnv deletes an environment variable for a service
-func (c *Client) DeleteServiceEnv(ctx context.Context, serviceUUID string, envUUID string) (*CreateResponse, error) {
-	path := fmt.Sprintf("/api/v1/services/%s/envs/%s", serviceUUID, envUUID)
+func (c *Client) DeleteServiceEnv(ctx context.Context, serviceUUID string, envUUID string, opts ...CallOption) (*CreateResponse, error) {
+	path := apiPath("services", serviceUUID, "envs", envUUID)
 	var response CreateResponse
-	err := c.doRequest(ctx, http.MethodDelete, path, nil, &response)
+	err := c.doRequest(ctx, "delete-env-by-service-uuid", http.MethodDelete, path, nil, &response, opts...)
 	return &response, err
 }
@@ -0,0 +1,95 @@
+package cagc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// streamTo is the call option used by StreamJSON to read the response body
+// incrementally instead of buffering it
+func streamTo(fn func(io.Reader) error) CallOption {
+	return callOptionFunc(func(o *callOptions) error {
+		o.stream = fn
+		return nil
+	})
+}
+
+// StreamJSON sends a raw API request whose response is a JSON array and calls
+// fn for each element as it is decoded, so memory use does not grow with the
+// size of the response. The maximum response size does not apply, and the
+// call is not retried once the response started streaming. An error returned
+// by fn stops the stream and is returned as is.
+func StreamJSON[T any](ctx context.Context, c *Client, req Request, fn func(T) error, opts ...CallOption) error {
+	opts = append(opts[:len(opts):len(opts)], streamTo(func(r io.Reader) error {
+		return decodeArray(r, fn)
+	}))
+	_, err := c.do(ctx, req, nil, opts...)
+	return err
+}
+
+// decodeArray decodes a JSON array from r element by element. A null body is
+// treated as an empty array.
+func decodeArray[T any](r io.Reader, fn func(T) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err == io.EOF || (err == nil && tok == nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("cagc: expected a JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// StreamApplications calls fn for every application without loading the
+// whole list into memory
+func (c *Client) StreamApplications(ctx context.Context, fn func(Application) error, opts ...CallOption) error {
+	return StreamJSON(ctx, c, Request{Operation: "list-applications", Method: http.MethodGet, Path: apiPath("applications")}, fn, opts...)
+}
+
+// StreamDatabases calls fn for every database without loading the whole list
+// into memory
+func (c *Client) StreamDatabases(ctx context.Context, fn func(Database) error, opts ...CallOption) error {
+	return StreamJSON(ctx, c, Request{Operation: "list-databases", Method: http.MethodGet, Path: apiPath("databases")}, fn, opts...)
+}
+
+// StreamDeployments calls fn for every running deployment, including its
+// logs, without loading the whole list into memory
+func (c *Client) StreamDeployments(ctx context.Context, fn func(Deployment) error, opts ...CallOption) error {
+	return StreamJSON(ctx, c, Request{Operation: "list-deployments", Method: http.MethodGet, Path: apiPath("deployments")}, fn, opts...)
+}
+
+// StreamResources calls fn for every resource without loading the whole list
+// into memory
+func (c *Client) StreamResources(ctx context.Context, fn func(Resource) error, opts ...CallOption) error {
+	return StreamJSON(ctx, c, Request{Operation: "list-resources", Method: http.MethodGet, Path: apiPath("resources")}, fn, opts...)
+}
+
+// StreamServers calls fn for every server without loading the whole list
+// into memory
+func (c *Client) StreamServers(ctx context.Context, fn func(Server) error, opts ...CallOption) error {
+	return StreamJSON(ctx, c, Request{Operation: "list-servers", Method: http.MethodGet, Path: apiPath("servers")}, fn, opts...)
+}
+
+// StreamServices calls fn for every service without loading the whole list
+// into memory
+func (c *Client) StreamServices(ctx context.Context, fn func(Service) error, opts ...CallOption) error {
+	return StreamJSON(ctx, c, Request{Operation: "list-services", Method: http.MethodGet, Path: apiPath("services")}, fn, opts...)
+}
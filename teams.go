@@ -2,43 +2,42 @@ package cagc
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
 // ListTeams lists all teams
-func (c *Client) ListTeams(ctx context.Context) ([]Team, error) {
+func (c *Client) ListTeams(ctx context.Context, opts ...CallOption) ([]Team, error) {
 	var teams []Team
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/teams", nil, &teams)
+	err := c.doRequest(ctx, "list-teams", http.MethodGet, apiPath("teams"), nil, &teams, opts...)
 	return teams, err
 }
 
 // GetTeam gets a team by ID
-func (c *Client) GetTeam(ctx context.Context, id string) (*Team, error) {
-	path := fmt.Sprintf("/api/v1/teams/%s", id)
+func (c *Client) GetTeam(ctx context.Context, id string, opts ...CallOption) (*Team, error) {
+	path := apiPath("teams", id)
 	var team Team
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &team)
+	err := c.doRequest(ctx, "get-team-by-id", http.MethodGet, path, nil, &team, opts...)
 	return &team, err
 }
 
 // GetTeamMembers gets members by team ID
-func (c *Client) GetTeamMembers(ctx context.Context, id string) ([]User, error) {
-	path := fmt.Sprintf("/api/v1/teams/%s/members", id)
+func (c *Client) GetTeamMembers(ctx context.Context, id string, opts ...CallOption) ([]User, error) {
+	path := apiPath("teams", id, "members")
 	var members []User
-	err := c.doRequest(ctx, http.MethodGet, path, nil, &members)
+	err := c.doRequest(ctx, "get-members-by-team-id", http.MethodGet, path, nil, &members, opts...)
 	return members, err
 }
 
 // GetCurrentTeam gets the currently authenticated team
-func (c *Client) GetCurrentTeam(ctx context.Context) (*Team, error) {
+func (c *Client) GetCurrentTeam(ctx context.Context, opts ...CallOption) (*Team, error) {
 	var team Team
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/teams/current", nil, &team)
+	err := c.doRequest(ctx, "get-current-team", http.MethodGet, apiPath("teams", "current"), nil, &team, opts...)
 	return &team, err
 }
 
 // GetCurrentTeamMembers gets the currently authenticated team members
-func (c *Client) GetCurrentTeamMembers(ctx context.Context) ([]User, error) {
+func (c *Client) GetCurrentTeamMembers(ctx context.Context, opts ...CallOption) ([]User, error) {
 	var members []User
-	err := c.doRequest(ctx, http.MethodGet, "/api/v1/teams/current/members", nil, &members)
+	err := c.doRequest(ctx, "get-current-team-members", http.MethodGet, apiPath("teams", "current", "members"), nil, &members, opts...)
 	return members, err
 }
@@ -0,0 +1,269 @@
+package cagc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ErrTLSVerification matches every *TLSError
+var ErrTLSVerification = errors.New("cagc: TLS verification failed")
+
+// ErrPinMismatch is wrapped by a *TLSError when no certificate presented by
+// the server matches the configured pins
+var ErrPinMismatch = errors.New("cagc: no certificate matches the configured pins")
+
+// TLSError explains why the TLS connection to a Coolify instance could not
+// be established
+type TLSError struct {
+	// Host is the host the client connected to
+	Host string
+	// Reason is a human readable explanation with a hint how to fix it
+	Reason string
+	// Err is the underlying error from crypto/tls or crypto/x509
+	Err error
+}
+
+// Error implements the error interface
+func (e *TLSError) Error() string {
+	return fmt.Sprintf("cagc: TLS connection to %s failed: %s: %v", e.Host, e.Reason, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TLSError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrTLSVerification) match
+func (e *TLSError) Is(target error) bool {
+	return target == ErrTLSVerification
+}
+
+// tlsSettings collects the TLS options until NewClient builds the transport
+type tlsSettings struct {
+	roots        *x509.CertPool
+	certificates []tls.Certificate
+	minVersion   uint16
+	certPins     [][]byte
+	spkiPins     [][]byte
+}
+
+// tlsOptions returns the TLS settings of the client, creating them on first use
+func (c *Client) tlsOptions() *tlsSettings {
+	if c.tls == nil {
+		c.tls = &tlsSettings{}
+	}
+	return c.tls
+}
+
+// WithCACertFile trusts the CA certificates in a PEM file in addition to the
+// system roots, e.g. for instances behind an internal CA or with a
+// self-signed certificate
+func WithCACertFile(path string) Option {
+	return optionFunc(func(c *Client) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cagc: reading CA file: %w", err)
+		}
+		return c.addRootCAs(data, path)
+	})
+}
+
+// WithCACertPEM trusts the PEM encoded CA certificates in addition to the
+// system roots
+func WithCACertPEM(pem []byte) Option {
+	return optionFunc(func(c *Client) error {
+		return c.addRootCAs(pem, "PEM data")
+	})
+}
+
+// addRootCAs adds PEM certificates to the trusted roots
+func (c *Client) addRootCAs(pem []byte, source string) error {
+	settings := c.tlsOptions()
+	if settings.roots == nil {
+		roots, err := x509.SystemCertPool()
+		if err != nil || roots == nil {
+			roots = x509.NewCertPool()
+		}
+		settings.roots = roots
+	}
+	if !settings.roots.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("cagc: no CA certificates found in %s", source)
+	}
+	return nil
+}
+
+// WithClientCertificate presents cert to servers that require mutual TLS
+func WithClientCertificate(cert tls.Certificate) Option {
+	return optionFunc(func(c *Client) error {
+		if len(cert.Certificate) == 0 {
+			return errors.New("cagc: WithClientCertificate requires a certificate")
+		}
+		settings := c.tlsOptions()
+		settings.certificates = append(settings.certificates, cert)
+		return nil
+	})
+}
+
+// WithClientCertificateFiles loads a PEM certificate and private key for
+// mutual TLS
+func WithClientCertificateFiles(certFile, keyFile string) Option {
+	return optionFunc(func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("cagc: loading client certificate: %w", err)
+		}
+		settings := c.tlsOptions()
+		settings.certificates = append(settings.certificates, cert)
+		return nil
+	})
+}
+
+// WithMinTLSVersion sets the minimum TLS version, e.g. tls.VersionTLS13
+func WithMinTLSVersion(version uint16) Option {
+	return optionFunc(func(c *Client) error {
+		if version < tls.VersionTLS10 || version > tls.VersionTLS13 {
+			return fmt.Errorf("cagc: unsupported TLS version %#04x", version)
+		}
+		c.tlsOptions().minVersion = version
+		return nil
+	})
+}
+
+// WithCertificatePins accepts the server only if a certificate of its chain
+// has one of the given SHA-256 fingerprints, in hex with or without colons
+// as printed by "openssl x509 -noout -fingerprint -sha256". Pinning applies
+// on top of the normal certificate verification.
+func WithCertificatePins(fingerprints ...string) Option {
+	return optionFunc(func(c *Client) error {
+		if len(fingerprints) == 0 {
+			return errors.New("cagc: WithCertificatePins requires at least one fingerprint")
+		}
+		settings := c.tlsOptions()
+		for _, fingerprint := range fingerprints {
+			pin, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+			if err != nil || len(pin) != sha256.Size {
+				return fmt.Errorf("cagc: invalid SHA-256 certificate fingerprint %q", fingerprint)
+			}
+			settings.certPins = append(settings.certPins, pin)
+		}
+		return nil
+	})
+}
+
+// WithSPKIPins accepts the server only if a certificate of its chain has a
+// public key with one of the given base64 SHA-256 SPKI hashes, optionally
+// prefixed with "sha256/". Unlike certificate pins they survive certificate
+// renewals that keep the key. Pinning applies on top of the normal
+// certificate verification.
+func WithSPKIPins(hashes ...string) Option {
+	return optionFunc(func(c *Client) error {
+		if len(hashes) == 0 {
+			return errors.New("cagc: WithSPKIPins requires at least one hash")
+		}
+		settings := c.tlsOptions()
+		for _, hash := range hashes {
+			pin, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(hash, "sha256/"))
+			if err != nil || len(pin) != sha256.Size {
+				return fmt.Errorf("cagc: invalid SHA-256 SPKI hash %q", hash)
+			}
+			settings.spkiPins = append(settings.spkiPins, pin)
+		}
+		return nil
+	})
+}
+
+// applyTLS installs the configured TLS settings on a clone of the client's
+// transport
+func (c *Client) applyTLS() error {
+	if c.tls == nil {
+		return nil
+	}
+
+	base := c.httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("cagc: TLS options require an *http.Transport, got %T", base)
+	}
+	transport = transport.Clone()
+
+	config := transport.TLSClientConfig
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if c.tls.roots != nil {
+		config.RootCAs = c.tls.roots
+	}
+	config.Certificates = append(config.Certificates, c.tls.certificates...)
+	if c.tls.minVersion != 0 {
+		config.MinVersion = c.tls.minVersion
+	}
+	if len(c.tls.certPins) > 0 || len(c.tls.spkiPins) > 0 {
+		config.VerifyConnection = c.tls.verifyPins
+	}
+
+	transport.TLSClientConfig = config
+	c.httpClient.Transport = transport
+	return nil
+}
+
+// verifyPins checks the server's certificates against the configured pins
+func (s *tlsSettings) verifyPins(cs tls.ConnectionState) error {
+	for _, cert := range cs.PeerCertificates {
+		certHash := sha256.Sum256(cert.Raw)
+		spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+		for _, pin := range s.certPins {
+			if bytes.Equal(pin, certHash[:]) {
+				return nil
+			}
+		}
+		for _, pin := range s.spkiPins {
+			if bytes.Equal(pin, spkiHash[:]) {
+				return nil
+			}
+		}
+	}
+	return ErrPinMismatch
+}
+
+// classifyTLSError turns TLS handshake failures into a *TLSError and returns
+// any other error unchanged
+func classifyTLSError(host string, err error) error {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		invalidErr       x509.CertificateInvalidError
+		recordErr        tls.RecordHeaderError
+	)
+	var reason string
+	switch {
+	case errors.Is(err, ErrPinMismatch):
+		reason = "certificate does not match the pinned fingerprints or keys"
+	case errors.As(err, &unknownAuthority):
+		reason = "certificate signed by an unknown authority; add the CA with WithCACertFile or WithCACertPEM"
+	case errors.As(err, &hostnameErr):
+		reason = "certificate is not valid for this host name"
+	case errors.As(err, &invalidErr):
+		if invalidErr.Reason == x509.Expired {
+			reason = "certificate has expired or is not yet valid"
+		} else {
+			reason = "certificate is invalid"
+		}
+	case errors.As(err, &recordErr):
+		reason = "server did not answer with TLS; check the URL scheme and port"
+	default:
+		return err
+	}
+	return &TLSError{Host: host, Reason: reason, Err: err}
+}
@@ -0,0 +1,102 @@
+package cagc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTLSServer starts an httptest TLS server answering every request with an
+// empty list and returns it with its certificate in PEM form
+func newTLSServer(t *testing.T) (*httptest.Server, []byte) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	// Rejected handshakes are expected, keep them out of the test output
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	return srv, certPEM
+}
+
+func TestTLSOptions(t *testing.T) {
+	srv, certPEM := newTLSServer(t)
+	certHash := sha256.Sum256(srv.Certificate().Raw)
+	spkiHash := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	wrongHash := sha256.Sum256([]byte("other key"))
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{"untrusted certificate", nil, ErrTLSVerification},
+		{"trusted CA", []Option{WithCACertPEM(certPEM)}, nil},
+		{"certificate pin match", []Option{
+			WithCACertPEM(certPEM),
+			WithCertificatePins(hex.EncodeToString(certHash[:])),
+		}, nil},
+		{"SPKI pin match", []Option{
+			WithCACertPEM(certPEM),
+			WithSPKIPins("sha256/" + base64.StdEncoding.EncodeToString(spkiHash[:])),
+		}, nil},
+		{"SPKI pin mismatch", []Option{
+			WithCACertPEM(certPEM),
+			WithSPKIPins(base64.StdEncoding.EncodeToString(wrongHash[:])),
+		}, ErrPinMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClient(srv.URL, "token", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = c.ListApplications(context.Background())
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			var tlsErr *TLSError
+			if !errors.As(err, &tlsErr) || tlsErr.Reason == "" {
+				t.Errorf("got %v, want a *TLSError with a reason", err)
+			}
+		})
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTLSOptionsRequireHTTPTransport(t *testing.T) {
+	custom := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("not reached")
+	})
+
+	_, err := NewClient("https://coolify.example.com", "token", WithTransport(custom), WithMinTLSVersion(tls.VersionTLS12))
+	if err == nil || !strings.Contains(err.Error(), "require an *http.Transport") {
+		t.Errorf("got %v, want an error asking for an *http.Transport", err)
+	}
+}
@@ -0,0 +1,166 @@
+package cagc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenProvider supplies the bearer token for API requests. After a response
+// matching ErrUnauthorized the client calls Invalidate, fetches a fresh token and retries the
+// request once if the token changed. Implementations must be safe for
+// concurrent use.
+type TokenProvider interface {
+	// Token returns the current API token
+	Token(ctx context.Context) (string, error)
+	// Invalidate discards any cached token so the next Token call fetches a new one
+	Invalidate()
+}
+
+// WithTokenProvider fetches the bearer token from p instead of using the
+// static token passed to NewClient
+func WithTokenProvider(p TokenProvider) Option {
+	return optionFunc(func(c *Client) error {
+		if p == nil {
+			return errors.New("cagc: WithTokenProvider requires a non-nil provider")
+		}
+		c.tokens = p
+		return nil
+	})
+}
+
+// StaticToken is a TokenProvider that always returns the same token
+type StaticToken string
+
+// Token implements TokenProvider
+func (t StaticToken) Token(context.Context) (string, error) {
+	return string(t), nil
+}
+
+// Invalidate implements TokenProvider; a static token cannot be refreshed
+func (t StaticToken) Invalidate() {}
+
+// EnvToken is a TokenProvider that reads the token from the named environment
+// variable on every request
+type EnvToken string
+
+// Token implements TokenProvider
+func (e EnvToken) Token(context.Context) (string, error) {
+	token := strings.TrimSpace(os.Getenv(string(e)))
+	if token == "" {
+		return "", fmt.Errorf("cagc: environment variable %s is not set", string(e))
+	}
+	return token, nil
+}
+
+// Invalidate implements TokenProvider; the variable is read on every request anyway
+func (e EnvToken) Invalidate() {}
+
+// FileToken is a TokenProvider that reads the token from a file and re-reads
+// it whenever the file changes, e.g. when a secret manager rotates it
+type FileToken struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileToken creates a FileToken reading from path
+func NewFileToken(path string) *FileToken {
+	return &FileToken{path: path}
+}
+
+// Token implements TokenProvider
+func (f *FileToken) Token(context.Context) (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("cagc: reading token file: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.token != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("cagc: reading token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("cagc: token file %s is empty", f.path)
+	}
+
+	f.token = token
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return token, nil
+}
+
+// Invalidate implements TokenProvider
+func (f *FileToken) Invalidate() {
+	f.mu.Lock()
+	f.token = ""
+	f.mu.Unlock()
+}
+
+// CommandToken is a TokenProvider that runs an external command, such as a
+// secret manager CLI, and uses its trimmed standard output as the token. The
+// output is cached until the token is invalidated.
+type CommandToken struct {
+	name string
+	args []string
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewCommandToken creates a CommandToken running name with args
+func NewCommandToken(name string, args ...string) *CommandToken {
+	return &CommandToken{name: name, args: args}
+}
+
+// Token implements TokenProvider
+func (t *CommandToken) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" {
+		return t.token, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.name, t.args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("cagc: token command %s: %w: %s", t.name, err, msg)
+		}
+		return "", fmt.Errorf("cagc: token command %s: %w", t.name, err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("cagc: token command %s printed no token", t.name)
+	}
+	t.token = token
+	return token, nil
+}
+
+// Invalidate implements TokenProvider
+func (t *CommandToken) Invalidate() {
+	t.mu.Lock()
+	t.token = ""
+	t.mu.Unlock()
+}
@@ -0,0 +1,176 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// rotatingToken hands out the next token after every Invalidate
+type rotatingToken struct {
+	mu     sync.Mutex
+	tokens []string
+	next   int
+}
+
+func (r *rotatingToken) Token(context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tokens[r.next], nil
+}
+
+func (r *rotatingToken) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.next < len(r.tokens)-1 {
+		r.next++
+	}
+}
+
+// unauthorized and invalidToken are the responses the API gives for a bad token
+func unauthorized(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusUnauthorized)
+	w.Write([]byte(`{"message":"Unauthenticated."}`))
+}
+
+func invalidToken(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte(`{"message":"Invalid token."}`))
+}
+
+func TestTokenRefresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		reject  func(http.ResponseWriter)
+		tokens  []string
+		valid   string
+		want    int32
+		wantErr error
+	}{
+		{"401 with a rotated token", unauthorized, []string{"old", "new"}, "new", 2, nil},
+		{"400 invalid token with a rotated token", invalidToken, []string{"old", "new"}, "new", 2, nil},
+		{"unchanged token is not retried", unauthorized, []string{"old"}, "new", 1, ErrUnauthorized},
+		{"refreshes only once", invalidToken, []string{"old", "new", "newer"}, "newer", 2, ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				if r.Header.Get("Authorization") != "Bearer "+tt.valid {
+					tt.reject(w)
+					return
+				}
+				w.Write([]byte(`[]`))
+			}, WithTokenProvider(&rotatingToken{tokens: tt.tokens}), WithRetryPolicy(fastRetries))
+
+			_, err := c.ListApplications(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&requests); got != tt.want {
+				t.Errorf("server saw %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens := NewFileToken(path)
+	ctx := context.Background()
+
+	if got, err := tokens.Token(ctx); err != nil || got != "old" {
+		t.Fatalf("Token() = %q, %v; want old", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("new\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens.Invalidate()
+	if got, err := tokens.Token(ctx); err != nil || got != "new" {
+		t.Fatalf("Token() after Invalidate = %q, %v; want new", got, err)
+	}
+
+	// A client holding the cached token picks up a rotation after a 401
+	if err := os.WriteFile(path, []byte("newer\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer newer" {
+			unauthorized(w)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}, WithTokenProvider(tokens))
+	if _, err := c.ListApplications(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens.Invalidate()
+	if _, err := tokens.Token(ctx); err == nil {
+		t.Error("expected an error for an empty token file")
+	}
+}
+
+func TestCommandToken(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	counter := filepath.Join(t.TempDir(), "calls")
+	tokens := NewCommandToken("sh", "-c", `echo x >> "$0"; printf 'token-%s\n' "$(wc -l < "$0" | tr -d ' ')"`, counter)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if got, err := tokens.Token(ctx); err != nil || got != "token-1" {
+			t.Fatalf("Token() = %q, %v; want the cached token-1", got, err)
+		}
+	}
+	tokens.Invalidate()
+	if got, err := tokens.Token(ctx); err != nil || got != "token-2" {
+		t.Fatalf("Token() after Invalidate = %q, %v; want token-2", got, err)
+	}
+
+	failing := NewCommandToken("sh", "-c", "echo locked >&2; exit 1")
+	if _, err := failing.Token(ctx); err == nil || !strings.Contains(err.Error(), "locked") {
+		t.Errorf("got %v, want an error with the command's stderr", err)
+	}
+	empty := NewCommandToken("sh", "-c", "true")
+	if _, err := empty.Token(ctx); err == nil {
+		t.Error("expected an error for a command printing no token")
+	}
+}
+
+func TestTokenProviderErrorStopsRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request sent without a token")
+	}))
+	defer srv.Close()
+	t.Setenv("CAGC_TEST_TOKEN", "")
+
+	c, err := NewClient(srv.URL, "", WithTokenProvider(EnvToken("CAGC_TEST_TOKEN")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListApplications(context.Background()); err == nil || !strings.Contains(err.Error(), "CAGC_TEST_TOKEN") {
+		t.Errorf("got %v, want an error naming the variable", err)
+	}
+}
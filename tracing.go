@@ -0,0 +1,127 @@
+package cagc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans created by the client
+const tracerName = "github.com/kerlexov/cagc"
+
+// Span attribute keys recorded for every API call
+const (
+	attrHTTPMethod   = attribute.Key("http.request.method")
+	attrHTTPStatus   = attribute.Key("http.response.status_code")
+	attrServerAddr   = attribute.Key("server.address")
+	attrOperation    = attribute.Key("cagc.operation")
+	attrResourceUUID = attribute.Key("cagc.resource.uuid")
+	attrRetryCount   = attribute.Key("cagc.retry.count")
+)
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used for the span
+// of every API call. By default the global provider is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return optionFunc(func(c *Client) error {
+		if tp == nil {
+			return errors.New("cagc: WithTracerProvider requires a non-nil provider")
+		}
+		c.tracerProvider = tp
+		return nil
+	})
+}
+
+// WithPropagator sets the propagator used to inject trace context into request
+// headers. By default the global propagator is used, or W3C trace context when
+// the application has not set one.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return optionFunc(func(c *Client) error {
+		if p == nil {
+			return errors.New("cagc: WithPropagator requires a non-nil propagator")
+		}
+		c.propagator = p
+		return nil
+	})
+}
+
+// startSpan starts the span of an API call, named after its operation
+func (c *Client) startSpan(ctx context.Context, call *Call) (context.Context, trace.Span) {
+	tp := c.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	attrs := []attribute.KeyValue{
+		attrOperation.String(call.Operation),
+		attrHTTPMethod.String(call.Method),
+		attrServerAddr.String(c.BaseURL.Hostname()),
+	}
+	if uuid := resourceUUID(call.Operation, call.Path); uuid != "" {
+		attrs = append(attrs, attrResourceUUID.String(uuid))
+	}
+
+	return tp.Tracer(tracerName).Start(ctx, call.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records the outcome of an API call and ends its span
+func endSpan(span trace.Span, call *Call, err error) {
+	if call.StatusCode > 0 {
+		span.SetAttributes(attrHTTPStatus.Int(call.StatusCode))
+	}
+	if call.Attempts > 1 {
+		span.SetAttributes(attrRetryCount.Int(call.Attempts - 1))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// injectTraceContext writes the trace context of ctx into the request headers
+func (c *Client) injectTraceContext(ctx context.Context, header http.Header) {
+	p := c.propagator
+	if p == nil {
+		p = otel.GetTextMapPropagator()
+		if len(p.Fields()) == 0 {
+			// No global propagator is set, which would inject nothing
+			p = propagation.TraceContext{}
+		}
+	}
+	p.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// resourceUUID extracts the UUID (or ID) of the resource an operation targets
+// from its path, e.g. "abc" for get-application-by-uuid on /applications/abc
+func resourceUUID(operation, path string) string {
+	if !strings.Contains(operation, "uuid") &&
+		!strings.HasSuffix(operation, "-by-id") &&
+		!strings.HasPrefix(operation, "execute-command") {
+		return ""
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) > 0 && segments[0] == "security" {
+		segments = segments[1:]
+	}
+	if len(segments) < 2 {
+		return ""
+	}
+
+	uuid, err := url.PathUnescape(segments[1])
+	if err != nil {
+		return segments[1]
+	}
+	return uuid
+}
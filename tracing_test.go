@@ -0,0 +1,75 @@
+package cagc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// testSpanContext is a sampled remote span context for propagation tests
+var testSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: trace.FlagsSampled,
+	Remote:     true,
+})
+
+func TestTraceContextIsPropagated(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantHeader string
+	}{
+		{"W3C trace context by default", nil, "traceparent"},
+		{"configured propagator", []Option{WithPropagator(propagation.Baggage{})}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Clone()
+				w.Write([]byte(`[]`))
+			}, tt.opts...)
+
+			ctx := trace.ContextWithRemoteSpanContext(context.Background(), testSpanContext)
+			if _, err := c.ListApplications(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			traceparent := header.Get("traceparent")
+			if tt.wantHeader == "" {
+				if traceparent != "" {
+					t.Errorf("got traceparent %q with a propagator that does not send it", traceparent)
+				}
+				return
+			}
+			if !strings.HasPrefix(traceparent, "00-"+testSpanContext.TraceID().String()+"-") {
+				t.Errorf("got traceparent %q, want one for trace %s", traceparent, testSpanContext.TraceID())
+			}
+		})
+	}
+}
+
+func TestResourceUUID(t *testing.T) {
+	tests := []struct {
+		operation string
+		path      string
+		want      string
+	}{
+		{"get-application-by-uuid", "/applications/abc", "abc"},
+		{"list-applications", "/applications", ""},
+		{"get-private-key-by-uuid", "/security/keys/k%2F1", "k/1"},
+		{"get-team-by-id", "/teams/7", "7"},
+		{"execute-command-application", "/applications/abc/execute", "abc"},
+	}
+	for _, tt := range tests {
+		if got := resourceUUID(tt.operation, tt.path); got != tt.want {
+			t.Errorf("resourceUUID(%q, %q) = %q, want %q", tt.operation, tt.path, got, tt.want)
+		}
+	}
+}
@@ -1,88 +1,9 @@
 package cagc
 
-import (
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"net/url"
-)
-
-// Client represents a cagc API client
-type Client struct {
-	BaseURL    *url.URL
-	httpClient *http.Client
-	token      string
-}
-
-// NewClient creates a new cagc API client
-func NewClient(baseURL string, token string) (*Client, error) {
-	parsedURL, err := url.Parse(baseURL)
-	if err != nil {
-		return nil, err
-	}
-
-	return &Client{
-		BaseURL:    parsedURL,
-		httpClient: http.DefaultClient,
-		token:      token,
-	}, nil
-}
-
-// doRequest performs an HTTP request and decodes the response into v if provided
-func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}, v interface{}) error {
-	u, err := c.BaseURL.Parse(path)
-	if err != nil {
-		return err
-	}
-
-	var req *http.Request
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return err
-		}
-		req, err = http.NewRequestWithContext(ctx, method, u.String(), bytes.NewBuffer(jsonBody))
-		if err != nil {
-			return err
-		}
-		req.Header.Set("Content-Type", "application/json")
-	} else {
-		req, err = http.NewRequestWithContext(ctx, method, u.String(), nil)
-		if err != nil {
-			return err
-		}
-	}
-
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("error reading error response: %v", err)
-		}
-		return fmt.Errorf("API error: %s, status code: %d", string(bodyBytes), resp.StatusCode)
-	}
-
-	if v != nil {
-		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 // Error represents an API error response
+//
+// Deprecated: failed requests return *APIError, which carries the parsed
+// message together with the status code and validation details.
 type Error struct {
 	Message string `json:"message"`
 }
@@ -178,6 +99,7 @@ type Database struct {
 	DestinationUUID         string `json:"destination_uuid,omitempty"`
 	Name                    string `json:"name,omitempty"`
 	Description             string `json:"description,omitempty"`
+	Status                  string `json:"status,omitempty"` // e.g. "running:healthy" or "exited"
 	Image                   string `json:"image,omitempty"`
 	IsPublic                bool   `json:"is_public,omitempty"`
 	PublicPort              int    `json:"public_port,omitempty"`
@@ -234,6 +156,97 @@ type Database struct {
 	MySQLConf         string `json:"mysql_conf,omitempty"`
 }
 
+// DatabaseCreateFields holds the fields shared by every database create
+// request. ServerUUID, ProjectUUID and EnvironmentName or EnvironmentUUID are
+// required.
+type DatabaseCreateFields struct {
+	ServerUUID              string `json:"server_uuid"`
+	ProjectUUID             string `json:"project_uuid"`
+	EnvironmentName         string `json:"environment_name,omitempty"`
+	EnvironmentUUID         string `json:"environment_uuid,omitempty"`
+	DestinationUUID         string `json:"destination_uuid,omitempty"`
+	Name                    string `json:"name,omitempty"`
+	Description             string `json:"description,omitempty"`
+	Image                   string `json:"image,omitempty"`
+	IsPublic                bool   `json:"is_public,omitempty"`
+	PublicPort              int    `json:"public_port,omitempty"`
+	LimitsMemory            string `json:"limits_memory,omitempty"`
+	LimitsMemorySwap        string `json:"limits_memory_swap,omitempty"`
+	LimitsMemorySwappiness  int    `json:"limits_memory_swappiness,omitempty"`
+	LimitsMemoryReservation string `json:"limits_memory_reservation,omitempty"`
+	LimitsCPUs              string `json:"limits_cpus,omitempty"`
+	LimitsCPUSet            string `json:"limits_cpuset,omitempty"`
+	LimitsCPUShares         int    `json:"limits_cpu_shares,omitempty"`
+	InstantDeploy           bool   `json:"instant_deploy,omitempty"`
+}
+
+// PostgresCreateRequest is the payload for creating a PostgreSQL database
+type PostgresCreateRequest struct {
+	DatabaseCreateFields
+	PostgresUser           string `json:"postgres_user,omitempty"`
+	PostgresPassword       string `json:"postgres_password,omitempty"`
+	PostgresDB             string `json:"postgres_db,omitempty"`
+	PostgresInitdbArgs     string `json:"postgres_initdb_args,omitempty"`
+	PostgresHostAuthMethod string `json:"postgres_host_auth_method,omitempty"`
+	PostgresConf           string `json:"postgres_conf,omitempty"`
+}
+
+// ClickhouseCreateRequest is the payload for creating a Clickhouse database
+type ClickhouseCreateRequest struct {
+	DatabaseCreateFields
+	ClickhouseAdminUser     string `json:"clickhouse_admin_user,omitempty"`
+	ClickhouseAdminPassword string `json:"clickhouse_admin_password,omitempty"`
+}
+
+// DragonflyCreateRequest is the payload for creating a DragonFly database
+type DragonflyCreateRequest struct {
+	DatabaseCreateFields
+	DragonflyPassword string `json:"dragonfly_password,omitempty"`
+}
+
+// RedisCreateRequest is the payload for creating a Redis database
+type RedisCreateRequest struct {
+	DatabaseCreateFields
+	RedisPassword string `json:"redis_password,omitempty"`
+	RedisConf     string `json:"redis_conf,omitempty"`
+}
+
+// KeyDBCreateRequest is the payload for creating a KeyDB database
+type KeyDBCreateRequest struct {
+	DatabaseCreateFields
+	KeyDBPassword string `json:"keydb_password,omitempty"`
+	KeyDBConf     string `json:"keydb_conf,omitempty"`
+}
+
+// MariaDBCreateRequest is the payload for creating a MariaDB database
+type MariaDBCreateRequest struct {
+	DatabaseCreateFields
+	MariaDBConf         string `json:"mariadb_conf,omitempty"`
+	MariaDBRootPassword string `json:"mariadb_root_password,omitempty"`
+	MariaDBUser         string `json:"mariadb_user,omitempty"`
+	MariaDBPassword     string `json:"mariadb_password,omitempty"`
+	MariaDBDatabase     string `json:"mariadb_database,omitempty"`
+}
+
+// MySQLCreateRequest is the payload for creating a MySQL database
+type MySQLCreateRequest struct {
+	DatabaseCreateFields
+	MySQLRootPassword string `json:"mysql_root_password,omitempty"`
+	MySQLPassword     string `json:"mysql_password,omitempty"`
+	MySQLUser         string `json:"mysql_user,omitempty"`
+	MySQLDatabase     string `json:"mysql_database,omitempty"`
+	MySQLConf         string `json:"mysql_conf,omitempty"`
+}
+
+// MongoDBCreateRequest is the payload for creating a MongoDB database
+type MongoDBCreateRequest struct {
+	DatabaseCreateFields
+	MongoConf               string `json:"mongo_conf,omitempty"`
+	MongoInitdbRootUsername string `json:"mongo_initdb_root_username,omitempty"`
+	MongoInitdbRootPassword string `json:"mongo_initdb_root_password,omitempty"`
+	MongoInitdbDatabase     string `json:"mongo_initdb_database,omitempty"`
+}
+
 // Server represents a cagc server
 type Server struct {
 	ID                            int            `json:"id,omitempty"`
@@ -336,9 +349,16 @@ type CreateResponse struct {
 // DeploymentResponse represents a response for deployment operations
 type DeploymentResponse struct {
 	Message        string `json:"message,omitempty"`
+	ResourceUUID   string `json:"resource_uuid,omitempty"`
 	DeploymentUUID string `json:"deployment_uuid,omitempty"`
 }
 
+// DeployResponse represents the response of DeployByTagOrUUID with one entry
+// per deployed resource
+type DeployResponse struct {
+	Deployments []DeploymentResponse `json:"deployments,omitempty"`
+}
+
 // CommandResponse represents a command execution response
 type CommandResponse struct {
 	Message  string `json:"message,omitempty"`
@@ -396,14 +416,46 @@ type Project struct {
 	UpdatedAt    string        `json:"updated_at,omitempty"`
 }
 
-// Environment represents a project environment
+// Environment represents a project environment. The resource lists are only
+// filled by GetEnvironment.
 type Environment struct {
 	ID          int    `json:"id,omitempty"`
+	UUID        string `json:"uuid,omitempty"`
 	Name        string `json:"name,omitempty"`
 	ProjectID   int    `json:"project_id,omitempty"`
 	CreatedAt   string `json:"created_at,omitempty"`
 	UpdatedAt   string `json:"updated_at,omitempty"`
 	Description string `json:"description,omitempty"`
+
+	Applications []Application `json:"applications,omitempty"`
+	Services     []Service     `json:"services,omitempty"`
+
+	// Databases by engine; use Databases for all of them
+	PostgreSQLs []Database `json:"postgresqls,omitempty"`
+	Redis       []Database `json:"redis,omitempty"`
+	MongoDBs    []Database `json:"mongodbs,omitempty"`
+	MySQLs      []Database `json:"mysqls,omitempty"`
+	MariaDBs    []Database `json:"mariadbs,omitempty"`
+	KeyDBs      []Database `json:"keydbs,omitempty"`
+	Dragonflies []Database `json:"dragonflies,omitempty"`
+	Clickhouses []Database `json:"clickhouses,omitempty"`
+}
+
+// Databases returns the databases of all engines in the environment
+func (e *Environment) Databases() []Database {
+	var databases []Database
+	for _, engine := range [][]Database{e.PostgreSQLs, e.Redis, e.MongoDBs, e.MySQLs, e.MariaDBs, e.KeyDBs, e.Dragonflies, e.Clickhouses} {
+		databases = append(databases, engine...)
+	}
+	return databases
+}
+
+// EnvironmentRef identifies a project environment by UUID, as needed by
+// create requests
+type EnvironmentRef struct {
+	ProjectUUID     string
+	EnvironmentUUID string
+	EnvironmentName string
 }
 
 // Destination represents a cagc destination